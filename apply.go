@@ -22,18 +22,33 @@ func (c *Client) ApplyFiles(filenames ...string) error {
 	return c.ApplyResource(r)
 }
 
-// ApplyResource applies the given resource. Create the resources with `ResultForFilenameParam` or `ResultForContent`
+// ApplyResource applies the given resource. Create the resources with `ResultForFilenameParam` or `ResultForContent`.
+// If it fails, the returned error is a *DiagnosticError when problems are
+// found in the objects that failed to be applied
 func (c *Client) ApplyResource(r *resource.Result) error {
 	if err := r.Err(); err != nil {
 		return err
 	}
 
-	// Is ServerSideApply requested
+	var failed []*resource.Info
+	applyFn := c.applier()
+	err := c.visit(r, func(info *resource.Info, err error) error {
+		if err := applyFn(info, err); err != nil {
+			failed = append(failed, info)
+			return err
+		}
+		return nil
+	}, c.enforcePolicy, c.createNamespace)
+	return c.withDiagnostics(err, failed)
+}
+
+// applier returns the visitor function to apply the resources, serverside
+// apply if it's requested
+func (c *Client) applier() resource.VisitorFunc {
 	if c.ServerSideApply {
-		return r.Visit(serverSideApply)
+		return serverSideApply
 	}
-
-	return r.Visit(apply)
+	return apply
 }
 
 func apply(info *resource.Info, err error) error {
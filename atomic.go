@@ -0,0 +1,227 @@
+package klient
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/kubectl/pkg/polymorphichelpers"
+)
+
+// readinessInterval is the time between checks of the objects readiness
+var readinessInterval = 2 * time.Second
+
+// RestorationReport is the result of restoring the objects changed by a
+// failed atomic apply
+type RestorationReport struct {
+	// Restored are the updated objects set back to their previous state
+	Restored []ObjectReference
+	// Deleted are the objects created by the failed apply
+	Deleted []ObjectReference
+	// Failed are the objects that could not be restored or deleted, with the error
+	Failed map[ObjectReference]error
+}
+
+func (r *RestorationReport) String() string {
+	var msgs []string
+	for _, obj := range r.Restored {
+		msgs = append(msgs, fmt.Sprintf("%s restored", obj))
+	}
+	for _, obj := range r.Deleted {
+		msgs = append(msgs, fmt.Sprintf("%s deleted", obj))
+	}
+	for obj, err := range r.Failed {
+		msgs = append(msgs, fmt.Sprintf("%s not restored: %s", obj, err))
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AtomicError is the error returned by an atomic apply that failed. Err is the
+// original error and Restoration the report of the objects restored
+type AtomicError struct {
+	Err         error
+	Restoration *RestorationReport
+}
+
+func (e *AtomicError) Error() string {
+	if len(e.Restoration.Failed) != 0 {
+		return fmt.Sprintf("%s. The restoration failed: %s", e.Err, e.Restoration)
+	}
+	return fmt.Sprintf("%s. The changes were reverted: %s", e.Err, e.Restoration)
+}
+
+func (e *AtomicError) Unwrap() error { return e.Err }
+
+// ApplyAtomic applies the given content in atomic mode, see ApplyAtomicResource
+func (c *Client) ApplyAtomic(content []byte, timeout time.Duration) error {
+	r := c.ResultForContent(content, nil)
+	return c.ApplyAtomicResource(r, timeout)
+}
+
+// ApplyAtomicFiles applies the resource(s) from the given filenames in atomic
+// mode, see ApplyAtomicResource
+func (c *Client) ApplyAtomicFiles(timeout time.Duration, filenames ...string) error {
+	r := c.ResultForFilenameParam(filenames, nil)
+	return c.ApplyAtomicResource(r, timeout)
+}
+
+// ApplyAtomicResource applies the given resource stopping at the first failure.
+// If an object fails, or the workloads (Deployments, DaemonSets and
+// StatefulSets) are not ready before the timeout, the updated objects are
+// restored to the state they had before the apply and the created objects are
+// deleted. The returned error is an *AtomicError with the restoration report.
+// If the timeout is zero the readiness is not checked
+func (c *Client) ApplyAtomicResource(r *resource.Result, timeout time.Duration) error {
+	if err := r.Err(); err != nil {
+		return err
+	}
+
+	snapshots := map[*resource.Info]runtime.Object{}
+	takeSnapshots := func(infos []*resource.Info) error {
+		for _, info := range infos {
+			current, err := resource.NewHelper(info.Client, info.Mapping).Get(info.Namespace, info.Name, false)
+			if err != nil {
+				if errors.IsNotFound(err) {
+					continue
+				}
+				return failedTo("retrieve current configuration", info, err)
+			}
+			snapshots[info] = current
+		}
+		return nil
+	}
+
+	var applied, failed []*resource.Info
+	var failure error
+	applyFn := c.applier()
+	err := c.visit(r, func(info *resource.Info, err error) error {
+		if failure != nil {
+			return nil
+		}
+		if failure = applyFn(info, err); failure != nil {
+			failed = []*resource.Info{info}
+			return failure
+		}
+		applied = append(applied, info)
+		return nil
+	}, c.enforcePolicy, c.createNamespace, takeSnapshots)
+
+	if err == nil && timeout > 0 {
+		failed, err = waitForReadiness(applied, timeout)
+	}
+	// Diagnose the failure before the objects are restored
+	err = c.withDiagnostics(err, failed)
+	if err == nil || len(applied) == 0 {
+		return err
+	}
+
+	return &AtomicError{
+		Err:         err,
+		Restoration: restore(applied, snapshots),
+	}
+}
+
+// restore sets the applied objects back to their snapshot, or deletes them if
+// they did not exist, in reverse order
+func restore(applied []*resource.Info, snapshots map[*resource.Info]runtime.Object) *RestorationReport {
+	report := &RestorationReport{Failed: map[ObjectReference]error{}}
+	for i := len(applied) - 1; i >= 0; i-- {
+		info := applied[i]
+		ref := objectReferences([]*resource.Info{info})[0]
+		helper := resource.NewHelper(info.Client, info.Mapping)
+
+		snapshot, existed := snapshots[info]
+		if !existed {
+			if _, err := helper.Delete(info.Namespace, info.Name); err != nil && !errors.IsNotFound(err) {
+				report.Failed[ref] = err
+				continue
+			}
+			report.Deleted = append(report.Deleted, ref)
+			continue
+		}
+
+		obj := snapshot.DeepCopyObject()
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			report.Failed[ref] = err
+			continue
+		}
+		// Let the helper use the current version to overwrite the object
+		accessor.SetResourceVersion("")
+		accessor.SetManagedFields(nil)
+		if _, err := helper.Replace(info.Namespace, info.Name, true, obj); err != nil {
+			report.Failed[ref] = err
+			continue
+		}
+		report.Restored = append(report.Restored, ref)
+	}
+	return report
+}
+
+// waitForReadiness waits until the rollout of every workload is complete. The
+// objects without rollout status are ready once they are applied. If it fails,
+// it returns the workloads not known to be ready
+func waitForReadiness(infos []*resource.Info, timeout time.Duration) ([]*resource.Info, error) {
+	pending := []*resource.Info{}
+	for _, info := range infos {
+		if _, err := polymorphichelpers.StatusViewerFn(info.Mapping); err == nil {
+			pending = append(pending, info)
+		}
+	}
+
+	var status string
+	err := wait.PollImmediate(readinessInterval, timeout, func() (bool, error) {
+		for len(pending) != 0 {
+			info := pending[0]
+			ready, msg, err := rolloutComplete(info)
+			if err != nil {
+				return false, err
+			}
+			if !ready {
+				status = msg
+				return false, nil
+			}
+			pending = pending[1:]
+		}
+		return true, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		return pending, fmt.Errorf("timed out waiting for %s to be ready. %s", objectReferences(pending[:1])[0], strings.TrimSpace(status))
+	}
+	if err != nil {
+		return pending, err
+	}
+	return nil, nil
+}
+
+// rolloutComplete returns true if the rollout of the workload is complete, or
+// the rollout status if it's not
+func rolloutComplete(info *resource.Info) (bool, string, error) {
+	viewer, err := polymorphichelpers.StatusViewerFn(info.Mapping)
+	if err != nil {
+		return false, "", err
+	}
+	current, err := resource.NewHelper(info.Client, info.Mapping).Get(info.Namespace, info.Name, false)
+	if err != nil {
+		return false, "", failedTo("retrieve current status", info, err)
+	}
+	u, ok := current.(runtime.Unstructured)
+	if !ok {
+		obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(current)
+		if err != nil {
+			return false, "", failedTo("convert current status", info, err)
+		}
+		u = &unstructured.Unstructured{Object: obj}
+	}
+	msg, done, err := viewer.Status(u, 0)
+	if err != nil {
+		return false, "", failedTo("get rollout status", info, err)
+	}
+	return done, msg, nil
+}
@@ -0,0 +1,49 @@
+package klient
+
+import (
+	"os"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestClient_ApplyAtomic(t *testing.T) {
+	envContext := os.Getenv(contextEnvVarName)
+	envKubeconfig := os.Getenv(kubeconfigEnvVarName)
+
+	c, err := NewE(envContext, envKubeconfig)
+	if err != nil {
+		t.Fatalf("failed to create the client with context %q and kubeconfig %q", envContext, envKubeconfig)
+	}
+
+	initial := []byte(`{"apiVersion": "v1", "kind": "ConfigMap", "metadata": { "name": "test-atomic-0" }, "data": { "key1": "apple" } }`)
+	if err := c.Apply(initial); err != nil {
+		t.Fatalf("Client.Apply() error = %v", err)
+	}
+	defer c.Delete(initial)
+
+	content := []byte(`{"apiVersion": "v1", "kind": "ConfigMap", "metadata": { "name": "test-atomic-0" }, "data": { "key1": "banana" } }
+{"apiVersion": "v1", "kind": "ConfigMap", "metadata": { "name": "test-atomic-1" }, "data": { "key1": "orange" } }
+{"apiVersion": "v1", "kind": "ConfigMap", "metadata": { "name": "Test_Atomic_2" }, "data": { "key1": "lemon" } }`)
+
+	err = c.ApplyAtomic(content, 0)
+	atomicErr, ok := err.(*AtomicError)
+	if !ok {
+		t.Fatalf("Client.ApplyAtomic() error = %v, want an *AtomicError", err)
+	}
+	if len(atomicErr.Restoration.Restored) != 1 || len(atomicErr.Restoration.Deleted) != 1 || len(atomicErr.Restoration.Failed) != 0 {
+		t.Errorf("Client.ApplyAtomic() restoration = %s, want 1 restored and 1 deleted", atomicErr.Restoration)
+	}
+
+	cm, err := c.Clientset.CoreV1().ConfigMaps("default").Get("test-atomic-0", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get the restored ConfigMap. error = %v", err)
+	}
+	if got := cm.Data["key1"]; got != "apple" {
+		t.Errorf("Client.ApplyAtomic() restored key1 = %q, want %q", got, "apple")
+	}
+	if _, err := c.Clientset.CoreV1().ConfigMaps("default").Get("test-atomic-1", metav1.GetOptions{}); !errors.IsNotFound(err) {
+		t.Errorf("Client.ApplyAtomic() did not delete the created ConfigMap. error = %v", err)
+	}
+}
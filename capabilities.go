@@ -0,0 +1,359 @@
+package klient
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/discovery"
+	"sigs.k8s.io/yaml"
+)
+
+// Capabilities are the features of the cluster: the server version and the
+// served APIs
+type Capabilities struct {
+	Version *version.Info
+	// GroupVersions are the served group/versions, i.e. `apps/v1` or `v1`
+	GroupVersions []string
+	// PreferredVersions are the preferred group/version of every group
+	PreferredVersions map[string]string
+	// Resources are the served resources of every group/version
+	Resources map[string][]metav1.APIResource
+	// ServerSideApply is true if the server supports server-side apply
+	ServerSideApply bool
+	// DryRun is true if the server supports server-side dry-run
+	DryRun bool
+}
+
+// Capabilities returns the version and the served APIs of the cluster, and
+// the features it supports. The groups that fail to be discovered, i.e. an
+// unavailable aggregated API, are not included
+func (c *Client) Capabilities() (*Capabilities, error) {
+	dc := c.Clientset.Discovery()
+	info, err := dc.ServerVersion()
+	if err != nil {
+		return nil, fmt.Errorf("cannot get the server version. %s", err)
+	}
+	groups, lists, err := dc.ServerGroupsAndResources()
+	if err != nil && !discovery.IsGroupDiscoveryFailedError(err) {
+		return nil, fmt.Errorf("cannot discover the served resources. %s", err)
+	}
+
+	caps := &Capabilities{
+		Version:           info,
+		PreferredVersions: map[string]string{},
+		Resources:         map[string][]metav1.APIResource{},
+	}
+	for _, group := range groups {
+		caps.PreferredVersions[group.Name] = group.PreferredVersion.GroupVersion
+	}
+	for _, list := range lists {
+		caps.GroupVersions = append(caps.GroupVersions, list.GroupVersion)
+		caps.Resources[list.GroupVersion] = list.APIResources
+	}
+
+	// Both features are enabled by default since they are beta
+	caps.DryRun = caps.AtLeast(1, 13)
+	caps.ServerSideApply = caps.AtLeast(1, 16)
+	return caps, nil
+}
+
+// AtLeast returns true if the server version is the given version or newer
+func (caps *Capabilities) AtLeast(major, minor int) bool {
+	if caps.Version == nil {
+		return false
+	}
+	// The minor version of some providers has a suffix, i.e. `16+`
+	serverMajor, _ := strconv.Atoi(strings.TrimRight(caps.Version.Major, "+"))
+	serverMinor, _ := strconv.Atoi(strings.TrimRight(caps.Version.Minor, "+"))
+	return serverMajor > major || (serverMajor == major && serverMinor >= minor)
+}
+
+// HasGroupVersion returns true if the group/version is served, i.e. `apps/v1`
+func (caps *Capabilities) HasGroupVersion(groupVersion string) bool {
+	_, ok := caps.Resources[groupVersion]
+	return ok
+}
+
+// HasResource returns true if the resource is served in the group/version,
+// i.e. `deployments` in `apps/v1`
+func (caps *Capabilities) HasResource(groupVersion, resource string) bool {
+	for _, r := range caps.Resources[groupVersion] {
+		if r.Name == resource {
+			return true
+		}
+	}
+	return false
+}
+
+// HasKind returns true if the kind is served in the group/version, i.e.
+// `Deployment` in `apps/v1`
+func (caps *Capabilities) HasKind(groupVersion, kind string) bool {
+	for _, r := range caps.Resources[groupVersion] {
+		// The subresources have the kind of other objects, i.e. Scale
+		if r.Kind == kind && !strings.Contains(r.Name, "/") {
+			return true
+		}
+	}
+	return false
+}
+
+// apiConversion is the conversion of kinds from a deprecated apiVersion to
+// a newer one. The convert function, if set, modifies the object to the new
+// apiVersion or fails if it's not possible without loss
+type apiConversion struct {
+	from, to string
+	kinds    []string
+	convert  func(obj map[string]interface{}) error
+}
+
+// apiConversions are the known conversions of deprecated apiVersions, sorted by
+// preference for the same kind
+var apiConversions = []apiConversion{
+	{"extensions/v1beta1", "apps/v1", []string{"Deployment"}, workloadConversion(map[string]interface{}{
+		"spec.revisionHistoryLimit":                  int64(math.MaxInt32),
+		"spec.progressDeadlineSeconds":               int64(math.MaxInt32),
+		"spec.strategy.rollingUpdate.maxSurge":       int64(1),
+		"spec.strategy.rollingUpdate.maxUnavailable": int64(1),
+	})},
+	{"extensions/v1beta1", "apps/v1", []string{"DaemonSet"}, workloadConversion(map[string]interface{}{
+		"spec.updateStrategy.type":  "OnDelete",
+		"spec.revisionHistoryLimit": int64(10),
+	})},
+	{"extensions/v1beta1", "apps/v1", []string{"ReplicaSet"}, workloadConversion(nil)},
+	{"apps/v1beta1", "apps/v1", []string{"Deployment"}, workloadConversion(map[string]interface{}{
+		"spec.revisionHistoryLimit":                  int64(2),
+		"spec.strategy.rollingUpdate.maxSurge":       "25%",
+		"spec.strategy.rollingUpdate.maxUnavailable": "25%",
+	})},
+	{"apps/v1beta1", "apps/v1", []string{"StatefulSet"}, workloadConversion(map[string]interface{}{
+		"spec.updateStrategy.type": "OnDelete",
+	})},
+	{"apps/v1beta2", "apps/v1", []string{"Deployment", "DaemonSet", "ReplicaSet", "StatefulSet"}, workloadConversion(nil)},
+	{"extensions/v1beta1", "networking.k8s.io/v1", []string{"Ingress"}, convertIngress},
+	{"extensions/v1beta1", "networking.k8s.io/v1beta1", []string{"Ingress"}, nil},
+	{"networking.k8s.io/v1beta1", "networking.k8s.io/v1", []string{"Ingress"}, convertIngress},
+	{"extensions/v1beta1", "networking.k8s.io/v1", []string{"NetworkPolicy"}, nil},
+	{"extensions/v1beta1", "policy/v1beta1", []string{"PodSecurityPolicy"}, nil},
+	{"rbac.authorization.k8s.io/v1beta1", "rbac.authorization.k8s.io/v1", []string{"Role", "ClusterRole", "RoleBinding", "ClusterRoleBinding"}, nil},
+	{"scheduling.k8s.io/v1beta1", "scheduling.k8s.io/v1", []string{"PriorityClass"}, nil},
+	{"storage.k8s.io/v1beta1", "storage.k8s.io/v1", []string{"StorageClass"}, nil},
+	{"batch/v1beta1", "batch/v1", []string{"CronJob"}, nil},
+	{"policy/v1beta1", "policy/v1", []string{"PodDisruptionBudget"}, convertPodDisruptionBudget},
+}
+
+// upgradeAPIVersions rewrites the deprecated apiVersions of the objects in the
+// manifests to a version served by the cluster. It fails if an object cannot
+// be converted without loss, or if its apiVersion is not served and there is
+// no conversion for it
+func (c *Client) upgradeAPIVersions(content []byte) ([]byte, error) {
+	caps, err := c.Capabilities()
+	if err != nil {
+		return nil, err
+	}
+	docs, err := decodeDocuments(content)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifests [][]byte
+	for _, doc := range docs {
+		if doc.obj == nil {
+			continue
+		}
+		if err := upgradeObject(doc.obj, caps); err != nil {
+			return nil, err
+		}
+		if items, ok := doc.obj["items"].([]interface{}); ok && strings.HasSuffix(doc.gvk.Kind, "List") {
+			for _, item := range items {
+				if obj, ok := item.(map[string]interface{}); ok {
+					if err := upgradeObject(obj, caps); err != nil {
+						return nil, err
+					}
+				}
+			}
+		}
+		data, err := yaml.Marshal(doc.obj)
+		if err != nil {
+			return nil, err
+		}
+		manifests = append(manifests, data)
+	}
+	return joinDocuments(manifests), nil
+}
+
+// upgradeObject rewrites the deprecated apiVersion of the object to the first
+// version served by the cluster it can be converted to
+func upgradeObject(obj map[string]interface{}, caps *Capabilities) error {
+	u := &unstructured.Unstructured{Object: obj}
+	apiVersion, kind := u.GetAPIVersion(), u.GetKind()
+
+	found := false
+	for _, conv := range apiConversions {
+		if conv.from != apiVersion || !containsString(conv.kinds, kind) {
+			continue
+		}
+		found = true
+		if !caps.HasKind(conv.to, kind) {
+			continue
+		}
+
+		// The object is not used if the conversion fails
+		if conv.convert != nil {
+			if err := conv.convert(obj); err != nil {
+				return fmt.Errorf("cannot convert %s %q from %s to %s. %s", kind, u.GetName(), apiVersion, conv.to, err)
+			}
+		}
+		obj["apiVersion"] = conv.to
+		return nil
+	}
+
+	if caps.HasKind(apiVersion, kind) {
+		return nil
+	}
+	if found {
+		return fmt.Errorf("the apiVersion %s of %s %q is not served and none of the versions it can be converted to is served", apiVersion, kind, u.GetName())
+	}
+	// The kinds of unknown APIs, i.e. custom resources, are left to the builder
+	return nil
+}
+
+// workloadConversion converts a workload to apps/v1: the selector is required,
+// so it's set from the labels of the template as the old versions do, and the
+// defaults of the old version are set explicitly to keep the same behavior
+func workloadConversion(defaults map[string]interface{}) func(obj map[string]interface{}) error {
+	return func(obj map[string]interface{}) error {
+		if _, ok, _ := unstructured.NestedFieldNoCopy(obj, "spec", "rollbackTo"); ok {
+			return fmt.Errorf("spec.rollbackTo is not supported, use a rollout undo")
+		}
+		if _, ok, _ := unstructured.NestedFieldNoCopy(obj, "spec", "templateGeneration"); ok {
+			return fmt.Errorf("spec.templateGeneration is not supported")
+		}
+		if _, ok, _ := unstructured.NestedFieldNoCopy(obj, "spec", "selector"); !ok {
+			labels, _, _ := unstructured.NestedMap(obj, "spec", "template", "metadata", "labels")
+			if len(labels) == 0 {
+				return fmt.Errorf("spec.selector is required and the template has no labels to default it")
+			}
+			if err := unstructured.SetNestedMap(obj, labels, "spec", "selector", "matchLabels"); err != nil {
+				return err
+			}
+		}
+
+		strategy, _, _ := unstructured.NestedString(obj, "spec", "strategy", "type")
+		for path, value := range defaults {
+			fields := strings.Split(path, ".")
+			// The rolling update parameters are only valid for that strategy
+			if fields[1] == "strategy" && strategy != "" && strategy != "RollingUpdate" {
+				continue
+			}
+			if _, ok, _ := unstructured.NestedFieldNoCopy(obj, fields...); !ok {
+				if err := unstructured.SetNestedField(obj, value, fields...); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+}
+
+// convertIngress converts an Ingress to networking.k8s.io/v1: the backends
+// reference the service with a name and a port number or name, the default
+// backend is renamed and the path type is required
+func convertIngress(obj map[string]interface{}) error {
+	if backend, ok, _ := unstructured.NestedMap(obj, "spec", "backend"); ok {
+		converted, err := convertIngressBackend(backend)
+		if err != nil {
+			return err
+		}
+		unstructured.RemoveNestedField(obj, "spec", "backend")
+		if err := unstructured.SetNestedMap(obj, converted, "spec", "defaultBackend"); err != nil {
+			return err
+		}
+	}
+
+	rules, _, _ := unstructured.NestedSlice(obj, "spec", "rules")
+	for _, r := range rules {
+		rule, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		paths, _, _ := unstructured.NestedSlice(rule, "http", "paths")
+		for i, p := range paths {
+			path, ok := p.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if backend, ok, _ := unstructured.NestedMap(path, "backend"); ok {
+				converted, err := convertIngressBackend(backend)
+				if err != nil {
+					return err
+				}
+				path["backend"] = converted
+			}
+			if _, ok := path["pathType"]; !ok {
+				path["pathType"] = "ImplementationSpecific"
+			}
+			paths[i] = path
+		}
+		if len(paths) != 0 {
+			if err := unstructured.SetNestedSlice(rule, paths, "http", "paths"); err != nil {
+				return err
+			}
+		}
+	}
+	if len(rules) != 0 {
+		return unstructured.SetNestedSlice(obj, rules, "spec", "rules")
+	}
+	return nil
+}
+
+func convertIngressBackend(backend map[string]interface{}) (map[string]interface{}, error) {
+	if _, ok := backend["resource"]; ok {
+		return backend, nil
+	}
+	name, _, _ := unstructured.NestedString(backend, "serviceName")
+	if name == "" {
+		return nil, fmt.Errorf("the ingress backend has no serviceName")
+	}
+	port := map[string]interface{}{}
+	switch p := backend["servicePort"].(type) {
+	case string:
+		if n, err := strconv.Atoi(p); err == nil {
+			port["number"] = int64(n)
+		} else {
+			port["name"] = p
+		}
+	case int64:
+		port["number"] = p
+	case float64:
+		port["number"] = int64(p)
+	default:
+		return nil, fmt.Errorf("the ingress backend of the service %q has an invalid servicePort", name)
+	}
+	return map[string]interface{}{
+		"service": map[string]interface{}{"name": name, "port": port},
+	}, nil
+}
+
+// convertPodDisruptionBudget converts a PodDisruptionBudget to policy/v1, the
+// empty selector selects no pods in policy/v1beta1 but all pods in policy/v1
+func convertPodDisruptionBudget(obj map[string]interface{}) error {
+	selector, _, _ := unstructured.NestedMap(obj, "spec", "selector")
+	if len(selector) == 0 {
+		return fmt.Errorf("an empty spec.selector selects no pods in policy/v1beta1 but all the pods in policy/v1")
+	}
+	return nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
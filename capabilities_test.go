@@ -0,0 +1,135 @@
+package klient
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/version"
+)
+
+func TestClient_Capabilities(t *testing.T) {
+	responses := map[string]interface{}{
+		"/version": version.Info{Major: "1", Minor: "16+", GitVersion: "v1.16.3"},
+		"/api":     metav1.APIVersions{Versions: []string{"v1"}},
+		"/apis": metav1.APIGroupList{Groups: []metav1.APIGroup{{
+			Name:             "apps",
+			Versions:         []metav1.GroupVersionForDiscovery{{GroupVersion: "apps/v1", Version: "v1"}},
+			PreferredVersion: metav1.GroupVersionForDiscovery{GroupVersion: "apps/v1", Version: "v1"},
+		}}},
+		"/api/v1": metav1.APIResourceList{GroupVersion: "v1", APIResources: []metav1.APIResource{
+			{Name: "pods", Kind: "Pod", Namespaced: true},
+		}},
+		"/apis/apps/v1": metav1.APIResourceList{GroupVersion: "apps/v1", APIResources: []metav1.APIResource{
+			{Name: "deployments", Kind: "Deployment", Namespaced: true},
+			{Name: "deployments/scale", Kind: "Scale", Namespaced: true},
+		}},
+	}
+	c := testServerClient(t, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		resp, ok := responses[req.URL.Path]
+		if !ok {
+			http.NotFound(w, req)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+
+	caps, err := c.Capabilities()
+	if err != nil {
+		t.Fatalf("Client.Capabilities() error = %v", err)
+	}
+	if caps.Version.GitVersion != "v1.16.3" || !caps.ServerSideApply || !caps.DryRun {
+		t.Errorf("Client.Capabilities() = %+v, want version v1.16.3 with server-side apply and dry-run", caps)
+	}
+	if got := caps.PreferredVersions["apps"]; got != "apps/v1" {
+		t.Errorf("Client.Capabilities() preferred version of apps = %q, want %q", got, "apps/v1")
+	}
+
+	tests := []struct {
+		name string
+		got  bool
+		want bool
+	}{
+		{"has group/version", caps.HasGroupVersion("apps/v1"), true},
+		{"has core group/version", caps.HasGroupVersion("v1"), true},
+		{"has no group/version", caps.HasGroupVersion("apps/v1beta1"), false},
+		{"has resource", caps.HasResource("apps/v1", "deployments"), true},
+		{"has kind", caps.HasKind("v1", "Pod"), true},
+		{"has no subresource kind", caps.HasKind("apps/v1", "Scale"), false},
+		{"at least", caps.AtLeast(1, 16), true},
+		{"not at least", caps.AtLeast(1, 17), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("Capabilities = %v, want %v", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpgradeObject(t *testing.T) {
+	caps := &Capabilities{Resources: map[string][]metav1.APIResource{
+		"apps/v1":              {{Name: "deployments", Kind: "Deployment"}, {Name: "daemonsets", Kind: "DaemonSet"}},
+		"networking.k8s.io/v1": {{Name: "ingresses", Kind: "Ingress"}, {Name: "networkpolicies", Kind: "NetworkPolicy"}},
+		"policy/v1beta1":       {{Name: "poddisruptionbudgets", Kind: "PodDisruptionBudget"}},
+		"policy/v1":            {{Name: "poddisruptionbudgets", Kind: "PodDisruptionBudget"}},
+		"v1":                   {{Name: "configmaps", Kind: "ConfigMap"}},
+	}}
+
+	tests := []struct {
+		name    string
+		obj     string
+		want    string
+		wantErr bool
+	}{
+		{"served and not deprecated", `{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "a"}}`,
+			`{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "a"}}`, false},
+		{"same schema", `{"apiVersion": "extensions/v1beta1", "kind": "NetworkPolicy", "metadata": {"name": "a"}, "spec": {"podSelector": {}}}`,
+			`{"apiVersion": "networking.k8s.io/v1", "kind": "NetworkPolicy", "metadata": {"name": "a"}, "spec": {"podSelector": {}}}`, false},
+		{"deployment defaults", `{"apiVersion": "extensions/v1beta1", "kind": "Deployment", "metadata": {"name": "a"}, "spec": {"template": {"metadata": {"labels": {"app": "a"}}}}}`,
+			`{"apiVersion": "apps/v1", "kind": "Deployment", "metadata": {"name": "a"}, "spec": {"selector": {"matchLabels": {"app": "a"}},
+				"revisionHistoryLimit": 2147483647, "progressDeadlineSeconds": 2147483647, "strategy": {"rollingUpdate": {"maxSurge": 1, "maxUnavailable": 1}},
+				"template": {"metadata": {"labels": {"app": "a"}}}}}`, false},
+		{"deployment recreate", `{"apiVersion": "apps/v1beta1", "kind": "Deployment", "metadata": {"name": "a"}, "spec": {"selector": {"matchLabels": {"app": "a"}}, "revisionHistoryLimit": 5, "strategy": {"type": "Recreate"}}}`,
+			`{"apiVersion": "apps/v1", "kind": "Deployment", "metadata": {"name": "a"}, "spec": {"selector": {"matchLabels": {"app": "a"}}, "revisionHistoryLimit": 5, "strategy": {"type": "Recreate"}}}`, false},
+		{"deployment without labels", `{"apiVersion": "extensions/v1beta1", "kind": "Deployment", "metadata": {"name": "a"}, "spec": {}}`, "", true},
+		{"deployment rollbackTo", `{"apiVersion": "extensions/v1beta1", "kind": "Deployment", "metadata": {"name": "a"}, "spec": {"selector": {}, "rollbackTo": {"revision": 1}}}`, "", true},
+		{"ingress", `{"apiVersion": "extensions/v1beta1", "kind": "Ingress", "metadata": {"name": "a"}, "spec": {"backend": {"serviceName": "web", "servicePort": 80},
+				"rules": [{"host": "a.com", "http": {"paths": [{"path": "/", "backend": {"serviceName": "api", "servicePort": "http"}}]}}]}}`,
+			`{"apiVersion": "networking.k8s.io/v1", "kind": "Ingress", "metadata": {"name": "a"}, "spec": {"defaultBackend": {"service": {"name": "web", "port": {"number": 80}}},
+				"rules": [{"host": "a.com", "http": {"paths": [{"path": "/", "pathType": "ImplementationSpecific", "backend": {"service": {"name": "api", "port": {"name": "http"}}}}]}}]}}`, false},
+		{"not served without conversion", `{"apiVersion": "extensions/v1beta1", "kind": "PodSecurityPolicy", "metadata": {"name": "a"}}`, "", true},
+		{"lossless conversion", `{"apiVersion": "policy/v1beta1", "kind": "PodDisruptionBudget", "metadata": {"name": "a"}, "spec": {"selector": {"matchLabels": {"app": "a"}}}}`,
+			`{"apiVersion": "policy/v1", "kind": "PodDisruptionBudget", "metadata": {"name": "a"}, "spec": {"selector": {"matchLabels": {"app": "a"}}}}`, false},
+		{"lossy conversion", `{"apiVersion": "policy/v1beta1", "kind": "PodDisruptionBudget", "metadata": {"name": "a"}, "spec": {}}`, "", true},
+		{"unknown kind", `{"apiVersion": "example.com/v1", "kind": "Fruit", "metadata": {"name": "a"}}`,
+			`{"apiVersion": "example.com/v1", "kind": "Fruit", "metadata": {"name": "a"}}`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := testInfo(t, tt.obj).Object.(*unstructured.Unstructured).Object
+			err := upgradeObject(obj, caps)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("upgradeObject() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			want := testInfo(t, tt.want).Object.(*unstructured.Unstructured).Object
+			gotJSON, _ := json.Marshal(obj)
+			wantJSON, _ := json.Marshal(want)
+			var got, expected interface{}
+			json.Unmarshal(gotJSON, &got)
+			json.Unmarshal(wantJSON, &expected)
+			if !reflect.DeepEqual(got, expected) {
+				t.Errorf("upgradeObject() = %s, want %s", gotJSON, strings.TrimSpace(string(wantJSON)))
+			}
+		})
+	}
+}
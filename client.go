@@ -4,9 +4,14 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"io/ioutil"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/kubectl/pkg/validation"
 )
@@ -24,6 +29,30 @@ type Client struct {
 	enforceNamespace bool
 	forceConflicts   bool
 	ServerSideApply  bool
+	// UpgradeAPIVersions rewrites the deprecated apiVersions of the objects in
+	// the manifests to a version served by the cluster, if the conversion is
+	// lossless. It fails if the conversion is not possible
+	UpgradeAPIVersions bool
+	// CheckDeprecations checks the apiVersions of the objects in the manifests
+	// before build them, warns about the deprecated ones with WarnDeprecations
+	// and fails if any is removed in the cluster version
+	CheckDeprecations bool
+	// WarnDeprecations, if set, is called with the objects using deprecated but
+	// not removed APIs when CheckDeprecations is true
+	WarnDeprecations func([]APIDeprecation)
+	// DryRun sends the requests to apply, create, replace or delete the objects
+	// as server-side dry-run, the objects are validated but not persisted
+	DryRun bool
+	// Policy are the rules to check before apply, create or replace resources
+	Policy *Policy
+	// ReleaseNamespace is the namespace of the releases inventory. If empty,
+	// it's the namespace from the kubeconfig context
+	ReleaseNamespace string
+	// ReleaseStorage is the Kind of the objects to store the releases inventory
+	ReleaseStorage ReleaseStorage
+	// ReleaseHistory is the maximum number of revisions to keep for every
+	// release. If zero, DefaultReleaseHistory revisions are kept
+	ReleaseHistory int
 }
 
 // Result is an alias for the Kubernetes CLI runtime resource.Result
@@ -38,6 +67,25 @@ type BuilderOptions struct {
 	FieldSelector string
 	All           bool
 	AllNamespaces bool
+	// NamespacePolicy is the action to take with the objects with a namespace
+	// different to the target namespace. The target namespace is Namespace or
+	// the namespace from the kubeconfig context
+	NamespacePolicy NamespacePolicy
+	// NamespacedOnly fails if there are cluster-scoped objects
+	NamespacedOnly bool
+	// CreateNamespace creates the target namespace if it does not exists when
+	// the resources are applied or created, unless it's a dry-run
+	CreateNamespace bool
+	// Strict validates the input before build the resources, even if Validate
+	// is false, rejecting unknown fields, duplicate keys, wrong types and empty
+	// documents
+	Strict bool
+	// Source are the options to fetch the filenames that are remote sources,
+	// such as Git repositories, tarballs or OCI artifacts
+	Source *SourceOptions
+	// Latest fetches the objects in the files from the server, like
+	// `kubectl get -f`, instead of using the objects as they are in the files
+	Latest bool
 }
 
 // NewBuilderOptions creates a BuilderOptions with the default values for
@@ -55,12 +103,18 @@ func NewE(context, kubeconfig string) (*Client, error) {
 
 	// If `true` it will always validate the given objects/resources
 	// Unless something different is specified in the NewBuilderOptions
-	validator, _ := factory.Validator(DefaultValidation)
+	validator, err := factory.Validator(DefaultValidation)
+	if err != nil {
+		// The OpenAPI schema is not available, the resources are not validated
+		// unless an offline validator is set with SetValidator
+		validator = validation.NullSchema{}
+	}
 
 	namespace, enforceNamespace, err := factory.ToRawKubeConfigLoader().Namespace()
 	if err != nil {
+		// The namespace is unknown, the objects are not enforced to the default one
 		namespace = v1.NamespaceDefault
-		enforceNamespace = true
+		enforceNamespace = false
 	}
 	clientset, err := factory.KubernetesClientSet()
 	if err != nil {
@@ -85,6 +139,38 @@ func New(context, kubeconfig string) *Client {
 	return client
 }
 
+// SetValidator sets the validator to use when the resources are validated,
+// i.e. an offline Validator created with NewValidatorFromFile or
+// NewValidatorForVersion
+func (c *Client) SetValidator(v *Validator) {
+	c.validator = v
+}
+
+// Validate validates the objects in the given content with the offline
+// validator, if set, or with the OpenAPI schema of the cluster. If the content
+// is not valid the returned error is a ValidationErrors
+func (c *Client) Validate(content []byte) error {
+	v, err := c.schemaValidator()
+	if err != nil {
+		return err
+	}
+	return v.Validate(content)
+}
+
+// schemaValidator returns the offline validator, if set, or a validator with
+// the OpenAPI schema of the cluster
+func (c *Client) schemaValidator() (*Validator, error) {
+	if v, ok := c.validator.(*Validator); ok {
+		return v, nil
+	}
+
+	resources, err := c.factory.OpenAPISchema()
+	if err != nil {
+		return nil, fmt.Errorf("cannot get the OpenAPI schema from the cluster. %s", err)
+	}
+	return NewValidator(resources), nil
+}
+
 // Builder creates a resource builder
 func (c *Client) builder(opt *BuilderOptions) *resource.Builder {
 	validator := c.validator
@@ -112,37 +198,138 @@ func (c *Client) builder(opt *BuilderOptions) *resource.Builder {
 		NamespaceParam(namespace).DefaultNamespace()
 }
 
-// ResultForFilenameParam returns the builder results for the given list of files or URLs
+// ResultForFilenameParam returns the builder results for the given list of
+// files, URLs or remote sources (see FetchSource)
 func (c *Client) ResultForFilenameParam(filenames []string, opt *BuilderOptions) *Result {
+	var srcOpt *SourceOptions
+	if opt != nil {
+		srcOpt = opt.Source
+	}
+
+	b := c.builder(opt)
+	var paths []string
+	for _, filename := range filenames {
+		if c.needsPreprocess(opt) {
+			content, err := c.readContent(filename, opt)
+			if err != nil {
+				b = b.AddError(err)
+				continue
+			}
+			b = b.Stream(bytes.NewReader(content), filename)
+			continue
+		}
+		if !IsRemoteSource(filename) {
+			paths = append(paths, filename)
+			continue
+		}
+		content, err := FetchSource(filename, srcOpt)
+		if err != nil {
+			b = b.AddError(err)
+			continue
+		}
+		b = b.Stream(bytes.NewReader(content), filename)
+	}
+
 	filenameOptions := &resource.FilenameOptions{
 		Recursive: false,
-		Filenames: filenames,
+		Filenames: paths,
 	}
 
-	return c.builder(opt).
+	b = b.
 		FilenameParam(c.enforceNamespace, filenameOptions).
-		Flatten().
-		Do()
+		Flatten()
+	if opt != nil && opt.Latest {
+		b = b.Latest()
+	}
+	r := b.Do()
+
+	return c.enforceNamespacePolicy(r, opt)
+}
+
+// needsPreprocess returns true if the manifests have to be read and processed
+// before build the resources with them
+func (c *Client) needsPreprocess(opt *BuilderOptions) bool {
+	return (opt != nil && opt.Strict) || c.UpgradeAPIVersions || c.CheckDeprecations
+}
+
+// readContent reads the given file, directory or URL, validating it in strict
+// mode, and processes the manifests
+func (c *Client) readContent(filename string, opt *BuilderOptions) ([]byte, error) {
+	var srcOpt *SourceOptions
+	validate := func([]byte, string) error { return nil }
+	if opt != nil {
+		srcOpt = opt.Source
+		if opt.Strict {
+			validate = c.validateStrict
+		}
+	}
+	content, err := c.readManifests(filename, srcOpt, validate)
+	if err != nil {
+		return nil, err
+	}
+	return c.preprocess(content)
+}
+
+// preprocess upgrades the apiVersions of the manifests and checks if they use
+// deprecated APIs, if it's requested
+func (c *Client) preprocess(content []byte) ([]byte, error) {
+	var err error
+	if c.UpgradeAPIVersions {
+		if content, err = c.upgradeAPIVersions(content); err != nil {
+			return nil, err
+		}
+	}
+	if c.CheckDeprecations {
+		if err := c.checkDeprecations(content); err != nil {
+			return nil, err
+		}
+	}
+	return content, nil
 }
 
 // ResultForReader returns the builder results for the given reader
 func (c *Client) ResultForReader(r io.Reader, opt *BuilderOptions) *Result {
-	return c.builder(opt).
+	b := c.builder(opt)
+	if c.needsPreprocess(opt) {
+		content, err := ioutil.ReadAll(r)
+		if err == nil && opt != nil && opt.Strict {
+			err = c.validateStrict(content, "")
+		}
+		if err == nil {
+			content, err = c.preprocess(content)
+		}
+		if err != nil {
+			return b.AddError(err).Do()
+		}
+		r = bytes.NewReader(content)
+	}
+
+	result := b.
 		Stream(r, "").
 		Flatten().
 		Do()
+
+	return c.enforceNamespacePolicy(result, opt)
 }
 
-// func (c *Client) ResultForName(opt *BuilderOptions, names ...string) *Result {
-// 	return c.builder(opt).
-// 		LabelSelectorParam(opt.LabelSelector).
-// 		FieldSelectorParam(opt.FieldSelector).
-// 		SelectAllParam(opt.All).
-// 		AllNamespaces(opt.AllNamespaces).
-// 		ResourceTypeOrNameArgs(false, names...).RequireObject(false).
-// 		Flatten().
-// 		Do()
-// }
+// ResultForArgs returns the builder results for the given arguments in the
+// format of `kubectl get`, i.e. `TYPE NAME...`, `TYPE/NAME...` or `TYPE` to
+// select the objects with the label and field selectors of the options
+func (c *Client) ResultForArgs(args []string, opt *BuilderOptions) *Result {
+	if opt == nil {
+		opt = NewBuilderOptions()
+	}
+	return c.builder(opt).
+		LabelSelectorParam(opt.LabelSelector).
+		FieldSelectorParam(opt.FieldSelector).
+		SelectAllParam(opt.All).
+		AllNamespaces(opt.AllNamespaces).
+		ResourceTypeOrNameArgs(true, args...).
+		RequireObject(true).
+		Flatten().
+		Latest().
+		Do()
+}
 
 // ResultForContent returns the builder results for the given content
 func (c *Client) ResultForContent(content []byte, opt *BuilderOptions) *Result {
@@ -150,6 +337,105 @@ func (c *Client) ResultForContent(content []byte, opt *BuilderOptions) *Result {
 	return c.ResultForReader(b, opt)
 }
 
+// visitHook is called with all the resources of a result before visit them.
+// If the hook fails none of the resources is visited
+type visitHook func(infos []*resource.Info) error
+
+// visit collects the resources of the result and visits every one of them
+// with the given function. The resources are collected before the visit, so
+// they can be modified or checked once the result is built, for example by the
+// namespace policy or the given hooks. Like `ContinueOnError`, a failure in one
+// resource does not stop the visit of the others, all the errors are returned
+func (c *Client) visit(r *Result, fn resource.VisitorFunc, hooks ...visitHook) error {
+	if err := r.Err(); err != nil {
+		return err
+	}
+
+	infos, err := r.Infos()
+	errs := []error{}
+	if err != nil {
+		errs = append(errs, err)
+	}
+	for _, hook := range hooks {
+		if err := hook(infos); err != nil {
+			return err
+		}
+	}
+	for _, info := range infos {
+		if c.DryRun {
+			dryRun(info)
+		}
+		if err := fn(info, nil); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// resourceInterface returns the dynamic client for the resource of the given
+// Kind, in the given namespace if the resource is namespaced, and its mapping
+func (c *Client) resourceInterface(gvk schema.GroupVersionKind, namespace string) (dynamic.ResourceInterface, *meta.RESTMapping, error) {
+	mapper, err := c.factory.ToRESTMapper()
+	if err != nil {
+		return nil, nil, err
+	}
+	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, nil, err
+	}
+	dyn, err := c.factory.DynamicClient()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		return dyn.Resource(mapping.Resource).Namespace(namespace), mapping, nil
+	}
+	return dyn.Resource(mapping.Resource), mapping, nil
+}
+
+// mappingFor returns the mapping of the given resource or kind, i.e.
+// `deployments`, `deployment.apps`, `Deployment` or `deployments.v1.apps`
+func (c *Client) mappingFor(resourceOrKind string) (*meta.RESTMapping, error) {
+	// From: k8s.io/cli-runtime/pkg/resource/builder.go > func (*Builder) mappingFor()
+	mapper, err := c.factory.ToRESTMapper()
+	if err != nil {
+		return nil, err
+	}
+
+	fullySpecifiedGVR, groupResource := schema.ParseResourceArg(resourceOrKind)
+	gvk := schema.GroupVersionKind{}
+	if fullySpecifiedGVR != nil {
+		gvk, _ = mapper.KindFor(*fullySpecifiedGVR)
+	}
+	if gvk.Empty() {
+		gvk, _ = mapper.KindFor(groupResource.WithVersion(""))
+	}
+	if !gvk.Empty() {
+		return mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	}
+
+	fullySpecifiedGVK, groupKind := schema.ParseKindArg(resourceOrKind)
+	if fullySpecifiedGVK == nil {
+		gvk := groupKind.WithVersion("")
+		fullySpecifiedGVK = &gvk
+	}
+	if !fullySpecifiedGVK.Empty() {
+		if mapping, err := mapper.RESTMapping(fullySpecifiedGVK.GroupKind(), fullySpecifiedGVK.Version); err == nil {
+			return mapping, nil
+		}
+	}
+	mapping, err := mapper.RESTMapping(groupKind)
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil, fmt.Errorf("the server doesn't have a resource type %q", groupResource.Resource)
+		}
+		return nil, err
+	}
+	return mapping, nil
+}
+
 func failedTo(action string, info *resource.Info, err error) error {
 	var resKind string
 	if info.Mapping != nil {
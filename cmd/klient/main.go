@@ -0,0 +1,350 @@
+// klient is a command-line tool to apply, create, delete, replace, diff, get
+// and wait for Kubernetes objects with the klient library, using the same
+// code path as the programs built on it.
+//
+// Usage:
+//
+//	klient apply -f deployment.yaml --server-side
+//	klient get deployment web -o yaml
+//	klient wait deployment/web --for condition=Available --timeout 2m
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/johandry/klient"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+// options are the flags shared by the commands
+type options struct {
+	context       string
+	kubeconfig    string
+	namespace     string
+	serverSide    bool
+	dryRun        string
+	output        string
+	filenames     []string
+	selector      string
+	allNamespaces bool
+	validate      bool
+	upgrade       bool
+	deprecations  bool
+	latest        bool
+}
+
+func main() {
+	if err := newRootCmd().Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+func newRootCmd() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:          "klient",
+		Short:        "klient manages Kubernetes objects with the klient library",
+		SilenceUsage: true,
+	}
+	flags := cmd.PersistentFlags()
+	flags.StringVar(&o.context, "context", "", "The name of the kubeconfig context to use")
+	flags.StringVar(&o.kubeconfig, "kubeconfig", "", "Path to the kubeconfig file to use")
+	flags.StringVarP(&o.namespace, "namespace", "n", "", "The namespace of the objects, if not set it's the namespace of the kubeconfig context")
+
+	cmd.AddCommand(
+		newModifyCmd(o, "apply", "Apply the objects from files, directories or URLs", "configured"),
+		newModifyCmd(o, "create", "Create the objects from files, directories or URLs", "created"),
+		newModifyCmd(o, "replace", "Replace the objects from files, directories or URLs", "replaced"),
+		newDeleteCmd(o),
+		newDiffCmd(o),
+		newGetCmd(o),
+		newWaitCmd(o),
+	)
+	return cmd
+}
+
+// client creates the klient client with the flags
+func (o *options) client() (*klient.Client, error) {
+	c, err := klient.NewE(o.context, o.kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	c.ServerSideApply = o.serverSide
+	c.DryRun = o.dryRun == "server"
+	c.UpgradeAPIVersions = o.upgrade
+	c.CheckDeprecations = o.deprecations
+	c.WarnDeprecations = func(deprecations []klient.APIDeprecation) {
+		for _, d := range deprecations {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", d)
+		}
+	}
+	return c, nil
+}
+
+// builderOptions returns the options to build the resources with the flags
+func (o *options) builderOptions() *klient.BuilderOptions {
+	opt := klient.NewBuilderOptions()
+	opt.Namespace = o.namespace
+	opt.Validate = o.validate
+	opt.LabelSelector = o.selector
+	opt.AllNamespaces = o.allNamespaces
+	opt.Latest = o.latest
+	return opt
+}
+
+// result returns the resources from the filenames, if any, or from the
+// arguments in the format `TYPE NAME...` or `TYPE/NAME...`
+func (o *options) result(c *klient.Client, args []string) (*resource.Result, error) {
+	if len(o.filenames) != 0 && len(args) != 0 {
+		return nil, fmt.Errorf("cannot use filenames and arguments at the same time")
+	}
+	if len(o.filenames) != 0 {
+		return c.ResultForFilenameParam(o.filenames, o.builderOptions()), nil
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("the filenames or the resource type and names are required")
+	}
+	return c.ResultForArgs(args, o.builderOptions()), nil
+}
+
+// printResult prints the objects of the result in the output format or, if
+// not set, the name of the objects with the operation done
+func (o *options) printResult(cmd *cobra.Command, c *klient.Client, r *resource.Result, operation string) error {
+	infos, err := r.Infos()
+	if err != nil {
+		return err
+	}
+	if o.output != "" {
+		return c.Print(cmd.OutOrStdout(), o.output, infos...)
+	}
+	switch o.dryRun {
+	case "server":
+		operation += " (server dry run)"
+	case "client":
+		operation += " (dry run)"
+	}
+	printer := &printers.NamePrinter{Operation: operation}
+	for _, info := range infos {
+		if err := printer.PrintObj(info.Object, cmd.OutOrStdout()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *options) addFilenameFlag(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVarP(&o.filenames, "filename", "f", nil, "Files, directories or URLs with the objects")
+}
+
+func (o *options) addModifyFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.dryRun, "dry-run", "none", "Must be \"none\", \"server\", or \"client\". With \"server\" the requests are sent as server-side dry-run, with \"client\" the objects are only printed")
+	cmd.Flags().StringVarP(&o.output, "output", "o", "", "Output format: json, yaml, name, wide, jsonpath=, go-template=, custom-columns=")
+	cmd.Flags().BoolVar(&o.validate, "validate", true, "Validate the objects with the schema before send them")
+	o.addUpgradeFlag(cmd)
+}
+
+func (o *options) addUpgradeFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&o.upgrade, "upgrade-api-versions", false, "Rewrite the deprecated apiVersions to a version served by the cluster, if the conversion is lossless")
+	cmd.Flags().BoolVar(&o.deprecations, "check-deprecations", false, "Warn about the objects using deprecated apiVersions and fail if any is removed in the cluster")
+}
+
+func (o *options) validateDryRun() error {
+	switch o.dryRun {
+	case "none", "server", "client":
+		return nil
+	}
+	return fmt.Errorf("invalid dry-run value %q, it must be \"none\", \"server\", or \"client\"", o.dryRun)
+}
+
+func newModifyCmd(o *options, name, short, operation string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   name + " -f FILENAME",
+		Short: short,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.validateDryRun(); err != nil {
+				return err
+			}
+			if len(o.filenames) == 0 {
+				return fmt.Errorf("the filenames are required, use -f")
+			}
+			c, err := o.client()
+			if err != nil {
+				return err
+			}
+			r, err := o.result(c, args)
+			if err != nil {
+				return err
+			}
+			if o.dryRun != "client" {
+				switch name {
+				case "apply":
+					err = c.ApplyResource(r)
+				case "create":
+					err = c.CreateResource(r)
+				case "replace":
+					err = c.ReplaceResource(r)
+				}
+				if err != nil {
+					return err
+				}
+			}
+			return o.printResult(cmd, c, r, operation)
+		},
+	}
+	o.addFilenameFlag(cmd)
+	o.addModifyFlags(cmd)
+	if name == "apply" {
+		cmd.Flags().BoolVar(&o.serverSide, "server-side", false, "Apply the objects with server-side apply")
+	}
+	return cmd
+}
+
+func newDeleteCmd(o *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete (-f FILENAME | TYPE [NAME...] | TYPE/NAME...)",
+		Short: "Delete the objects from files, directories, URLs or by type and name",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.validateDryRun(); err != nil {
+				return err
+			}
+			c, err := o.client()
+			if err != nil {
+				return err
+			}
+			r, err := o.result(c, args)
+			if err != nil {
+				return err
+			}
+			if o.dryRun != "client" {
+				if err := c.DeleteResource(r); err != nil {
+					return err
+				}
+			}
+			o.output = ""
+			return o.printResult(cmd, c, r, "deleted")
+		},
+	}
+	o.addFilenameFlag(cmd)
+	cmd.Flags().StringVar(&o.dryRun, "dry-run", "none", "Must be \"none\", \"server\", or \"client\". With \"server\" the requests are sent as server-side dry-run, with \"client\" the objects are only printed")
+	cmd.Flags().StringVarP(&o.selector, "selector", "l", "", "Label selector to filter the objects")
+	cmd.Flags().BoolVar(&o.validate, "validate", true, "Validate the objects with the schema before send them")
+	return cmd
+}
+
+func newDiffCmd(o *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff -f FILENAME",
+		Short: "Show the differences between the live objects and the objects as they would be applied",
+		Long:  "Show the differences between the live objects and the objects as they would be applied. It exits with status 1 if there are differences",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(o.filenames) == 0 {
+				return fmt.Errorf("the filenames are required, use -f")
+			}
+			c, err := o.client()
+			if err != nil {
+				return err
+			}
+			r, err := o.result(c, args)
+			if err != nil {
+				return err
+			}
+			diff, err := c.DiffResource(r)
+			if err != nil {
+				return err
+			}
+			if diff != "" {
+				fmt.Fprint(cmd.OutOrStdout(), diff)
+				os.Exit(1)
+			}
+			return nil
+		},
+	}
+	o.addFilenameFlag(cmd)
+	cmd.Flags().BoolVar(&o.serverSide, "server-side", false, "Compare with the objects as they would be applied with server-side apply")
+	o.addUpgradeFlag(cmd)
+	cmd.Flags().BoolVar(&o.validate, "validate", true, "Validate the objects with the schema before send them")
+	return cmd
+}
+
+func newGetCmd(o *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get (-f FILENAME | TYPE [NAME...] | TYPE/NAME...)",
+		Short: "Display the objects from files, directories, URLs or by type and name",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// The objects in the files are displayed as they are in the cluster
+			o.latest = true
+			c, err := o.client()
+			if err != nil {
+				return err
+			}
+			r, err := o.result(c, args)
+			if err != nil {
+				return err
+			}
+			if err := r.Err(); err != nil {
+				return err
+			}
+			infos, err := r.Infos()
+			if err != nil {
+				return err
+			}
+			if len(infos) == 0 {
+				fmt.Fprintln(cmd.ErrOrStderr(), "No resources found")
+				return nil
+			}
+			return c.Print(cmd.OutOrStdout(), o.output, infos...)
+		},
+	}
+	o.addFilenameFlag(cmd)
+	cmd.Flags().StringVarP(&o.output, "output", "o", "", "Output format: json, yaml, name, wide, jsonpath=, go-template=, custom-columns=")
+	cmd.Flags().StringVarP(&o.selector, "selector", "l", "", "Label selector to filter the objects")
+	cmd.Flags().BoolVarP(&o.allNamespaces, "all-namespaces", "A", false, "List the objects in all the namespaces")
+	return cmd
+}
+
+func newWaitCmd(o *options) *cobra.Command {
+	var condition string
+	var timeout time.Duration
+	cmd := &cobra.Command{
+		Use:   "wait (-f FILENAME | TYPE [NAME...] | TYPE/NAME...) --for CONDITION",
+		Short: "Wait for a condition on the objects",
+		Long:  "Wait for a condition on the objects. The condition is \"delete\" or \"condition=NAME[=VALUE]\", the value is \"True\" if not set",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := o.client()
+			if err != nil {
+				return err
+			}
+			r, err := o.result(c, args)
+			if err != nil {
+				return err
+			}
+			if err := c.Wait(r, condition, timeout); err != nil {
+				return err
+			}
+			operation := "condition met"
+			if condition == "delete" {
+				operation = "deleted"
+			}
+			infos, _ := r.Infos()
+			printer := &printers.NamePrinter{Operation: operation}
+			for _, info := range infos {
+				if err := printer.PrintObj(info.Object, cmd.OutOrStdout()); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	o.addFilenameFlag(cmd)
+	cmd.Flags().StringVar(&condition, "for", "", "The condition to wait for: \"delete\" or \"condition=NAME[=VALUE]\"")
+	cmd.Flags().DurationVar(&timeout, "timeout", 30*time.Second, "The time to wait before giving up, zero means check once")
+	cmd.Flags().StringVarP(&o.selector, "selector", "l", "", "Label selector to filter the objects")
+	cmd.MarkFlagRequired("for")
+	return cmd
+}
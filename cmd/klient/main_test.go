@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOptions_validateDryRun(t *testing.T) {
+	tests := []struct {
+		dryRun  string
+		wantErr bool
+	}{
+		{"none", false},
+		{"server", false},
+		{"client", false},
+		{"all", true},
+		{"", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dryRun, func(t *testing.T) {
+			o := &options{dryRun: tt.dryRun}
+			if err := o.validateDryRun(); (err != nil) != tt.wantErr {
+				t.Errorf("options.validateDryRun() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestOptions_result(t *testing.T) {
+	tests := []struct {
+		name      string
+		filenames []string
+		args      []string
+		wantErr   string
+	}{
+		{"filenames and arguments", []string{"cm.yaml"}, []string{"configmap"}, "at the same time"},
+		{"nothing", nil, nil, "are required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &options{filenames: tt.filenames}
+			if _, err := o.result(nil, tt.args); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("options.result() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// testServer returns the kubeconfig of a fake API server with the ConfigMap
+// `web` in the namespace `test`
+func testServer(t *testing.T, dir string) string {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch req.URL.Path {
+		case "/api":
+			w.Write([]byte(`{"kind": "APIVersions", "versions": ["v1"]}`))
+		case "/apis":
+			w.Write([]byte(`{"kind": "APIGroupList", "groups": []}`))
+		case "/api/v1":
+			w.Write([]byte(`{"kind": "APIResourceList", "groupVersion": "v1", "resources": [
+				{"name": "configmaps", "singularName": "", "namespaced": true, "kind": "ConfigMap", "verbs": ["get", "list"]}]}`))
+		case "/api/v1/namespaces/test/configmaps/web":
+			w.Write([]byte(`{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "web", "namespace": "test", "uid": "1"}, "data": {"color": "live"}}`))
+		default:
+			http.NotFound(w, req)
+		}
+	}))
+	t.Cleanup(ts.Close)
+
+	kubeconfig := filepath.Join(dir, "config")
+	config := fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+- name: test
+  cluster:
+    server: %s
+contexts:
+- name: test
+  context:
+    cluster: test
+    namespace: test
+current-context: test
+`, ts.URL)
+	if err := ioutil.WriteFile(kubeconfig, []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return kubeconfig
+}
+
+func TestGetCmd(t *testing.T) {
+	dir, err := ioutil.TempDir("", "klient")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	kubeconfig := testServer(t, dir)
+	manifest := filepath.Join(dir, "cm.yaml")
+	content := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: web\ndata:\n  color: local\n"
+	if err := ioutil.WriteFile(manifest, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	cmd := newRootCmd()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--kubeconfig", kubeconfig, "get", "-f", manifest, "-o", "jsonpath={.data.color}"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("klient get error = %v", err)
+	}
+	if got := out.String(); got != "live" {
+		t.Errorf("klient get = %q, want the live object %q", got, "live")
+	}
+}
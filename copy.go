@@ -0,0 +1,186 @@
+package klient
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// CopyToPod copies the local file or directory to the path in the container
+// of the pod, like `kubectl cp`. The files are sent in a tar archive through
+// exec, so the container requires the `tar` command. The file permissions are
+// preserved
+func (c *Client) CopyToPod(ctx context.Context, namespace, pod, container, src, dest string) error {
+	if _, err := os.Stat(src); err != nil {
+		return fmt.Errorf("cannot copy %q. %s", src, err)
+	}
+
+	reader, writer := io.Pipe()
+	go func() {
+		writer.CloseWithError(writeTar(writer, src, path.Base(dest)))
+	}()
+
+	destDir := path.Dir(dest)
+	cmd := []string{"tar", "-xmf", "-", "-C", destDir}
+	var stderr bytes.Buffer
+	if err := c.Exec(ctx, namespace, pod, container, cmd, reader, &bytes.Buffer{}, &stderr, false); err != nil {
+		return fmt.Errorf("cannot copy %q to %s:%s. %s %s", src, pod, dest, err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// CopyFromPod copies the file or directory in the path of the container of the
+// pod to the local destination, like `kubectl cp`. The files are received in a
+// tar archive through exec, so the container requires the `tar` command. The
+// archive entries outside of the destination are rejected and the links are
+// not copied, the returned skipped are the links in the archive
+func (c *Client) CopyFromPod(ctx context.Context, namespace, pod, container, src, dest string) (skipped []string, err error) {
+	reader, writer := io.Pipe()
+	var stderr bytes.Buffer
+	errCh := make(chan error, 1)
+	go func() {
+		cmd := []string{"tar", "cf", "-", "-C", path.Dir(src), path.Base(src)}
+		err := c.Exec(ctx, namespace, pod, container, cmd, nil, writer, &stderr, false)
+		if err != nil {
+			err = fmt.Errorf("%s %s", err, strings.TrimSpace(stderr.String()))
+		}
+		writer.CloseWithError(err)
+		errCh <- err
+	}()
+
+	skipped, err = readTar(reader, path.Base(src), dest)
+	if err != nil {
+		reader.CloseWithError(err)
+		return skipped, fmt.Errorf("cannot copy %s:%s to %q. %s", pod, src, dest, err)
+	}
+	// tar pads the archive after the end marker, it's read so exec can finish
+	io.Copy(ioutil.Discard, reader)
+	if err := <-errCh; err != nil {
+		return skipped, fmt.Errorf("cannot copy %s:%s to %q. %s", pod, src, dest, err)
+	}
+	return skipped, nil
+}
+
+// writeTar writes the file or directory src to the tar archive, with the
+// given name as the root of the archive
+func writeTar(w io.Writer, src, name string) error {
+	tw := tar.NewWriter(w)
+	err := filepath.Walk(src, func(file string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, file)
+		if err != nil {
+			return err
+		}
+
+		var link string
+		if fi.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(file); err != nil {
+				return err
+			}
+		}
+		hdr, err := tar.FileInfoHeader(fi, link)
+		if err != nil {
+			return err
+		}
+		hdr.Name = path.Join(name, filepath.ToSlash(rel))
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if !fi.Mode().IsRegular() {
+			return nil
+		}
+
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+// readTar extracts the tar archive with the given root name to the destination,
+// rejecting the entries that would be written outside of it. The links are
+// skipped, they are returned
+func readTar(r io.Reader, name, dest string) (skipped []string, err error) {
+	dest, err = filepath.Abs(dest)
+	if err != nil {
+		return nil, err
+	}
+	// Copy into an existing directory like `cp`
+	if fi, err := os.Stat(dest); err == nil && fi.IsDir() {
+		dest = filepath.Join(dest, name)
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return skipped, nil
+		}
+		if err != nil {
+			return skipped, err
+		}
+
+		target, err := tarEntryPath(hdr.Name, name, dest)
+		if err != nil {
+			return skipped, err
+		}
+		mode := os.FileMode(hdr.Mode).Perm()
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, mode|0700); err != nil {
+				return skipped, err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return skipped, err
+			}
+			f, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode)
+			if err != nil {
+				return skipped, err
+			}
+			if _, err := io.Copy(f, tr); err != nil {
+				f.Close()
+				return skipped, err
+			}
+			if err := f.Close(); err != nil {
+				return skipped, err
+			}
+		case tar.TypeSymlink, tar.TypeLink:
+			// The links may point outside of the destination
+			skipped = append(skipped, hdr.Name)
+		}
+	}
+}
+
+// tarEntryPath returns the local path of the archive entry, or an error if
+// it's outside of the destination
+func tarEntryPath(entry, name, dest string) (string, error) {
+	clean := path.Clean("/" + filepath.ToSlash(entry))
+	rel := strings.TrimPrefix(strings.TrimPrefix(clean, "/"+name), "/")
+	if clean != "/"+name && !strings.HasPrefix(clean, "/"+name+"/") {
+		return "", fmt.Errorf("the archive entry %q is outside of %q", entry, name)
+	}
+
+	target := filepath.Join(dest, filepath.FromSlash(rel))
+	if target != dest && !strings.HasPrefix(target, dest+string(filepath.Separator)) {
+		return "", fmt.Errorf("the archive entry %q is outside of the destination", entry)
+	}
+	return target, nil
+}
@@ -0,0 +1,177 @@
+package klient
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/apimachinery/pkg/util/httpstream/spdy"
+)
+
+func TestWriteTar_readTar(t *testing.T) {
+	dir, err := ioutil.TempDir("", "klient-copy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	src := filepath.Join(dir, "src")
+	if err := os.MkdirAll(filepath.Join(src, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(src, "run.sh"), []byte("#!/bin/sh\n"), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(src, "sub", "data.txt"), []byte("data"), 0640); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("/etc/passwd", filepath.Join(src, "passwd")); err != nil {
+		t.Fatal(err)
+	}
+
+	var archive bytes.Buffer
+	if err := writeTar(&archive, src, "seed"); err != nil {
+		t.Fatalf("writeTar() error = %v", err)
+	}
+	dest := filepath.Join(dir, "dest")
+	skipped, err := readTar(&archive, "seed", dest)
+	if err != nil {
+		t.Fatalf("readTar() error = %v", err)
+	}
+	if want := []string{"seed/passwd"}; !reflect.DeepEqual(skipped, want) {
+		t.Errorf("readTar() skipped = %v, want %v", skipped, want)
+	}
+
+	tests := []struct {
+		file     string
+		content  string
+		wantMode os.FileMode
+	}{
+		{"run.sh", "#!/bin/sh\n", 0750},
+		{"sub/data.txt", "data", 0640},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			file := filepath.Join(dest, filepath.FromSlash(tt.file))
+			content, err := ioutil.ReadFile(file)
+			if err != nil {
+				t.Fatalf("readTar() did not extract %q. %s", tt.file, err)
+			}
+			if string(content) != tt.content {
+				t.Errorf("readTar() content = %q, want %q", content, tt.content)
+			}
+			fi, _ := os.Stat(file)
+			if got := fi.Mode().Perm(); got != tt.wantMode {
+				t.Errorf("readTar() mode = %v, want %v", got, tt.wantMode)
+			}
+		})
+	}
+}
+
+func TestReadTar_pathTraversal(t *testing.T) {
+	tests := []struct {
+		name    string
+		entry   string
+		wantErr bool
+	}{
+		{"inside", "seed/data.txt", false},
+		{"root", "seed", false},
+		{"parent", "seed/../../evil.txt", true},
+		{"absolute", "/etc/evil.txt", true},
+		{"other root", "other/data.txt", true},
+		{"prefix of root", "seed-evil/data.txt", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "klient-copy")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			var archive bytes.Buffer
+			tw := tar.NewWriter(&archive)
+			tw.WriteHeader(&tar.Header{Name: tt.entry, Mode: 0644, Size: 4, Typeflag: tar.TypeReg})
+			tw.Write([]byte("evil"))
+			tw.Close()
+
+			dest := filepath.Join(dir, "dest")
+			if _, err := readTar(&archive, "seed", dest); (err != nil) != tt.wantErr {
+				t.Errorf("readTar() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// tarExecHandler is a fake exec endpoint that writes the archive to stdout
+// padded to a 10240 bytes record, like GNU tar
+func tarExecHandler(t *testing.T, archive []byte) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if _, err := httpstream.Handshake(req, w, []string{"v4.channel.k8s.io"}); err != nil {
+			t.Errorf("failed the exec handshake. %s", err)
+			return
+		}
+		streamCh := make(chan httpstream.Stream, 3)
+		conn := spdy.NewResponseUpgrader().UpgradeResponse(w, req, func(s httpstream.Stream, replySent <-chan struct{}) error {
+			streamCh <- s
+			return nil
+		})
+		if conn == nil {
+			return
+		}
+		defer conn.Close()
+
+		streams := map[string]httpstream.Stream{}
+		for i := 0; i < 3; i++ {
+			s := <-streamCh
+			streams[s.Headers().Get(corev1.StreamType)] = s
+		}
+		padded := make([]byte, 10240)
+		copy(padded, archive)
+		streams[corev1.StreamTypeStdout].Write(padded)
+		for _, s := range streams {
+			s.Close()
+		}
+	}
+}
+
+func TestClient_CopyFromPod(t *testing.T) {
+	var archive bytes.Buffer
+	tw := tar.NewWriter(&archive)
+	tw.WriteHeader(&tar.Header{Name: "seed/data.txt", Mode: 0644, Size: 4, Typeflag: tar.TypeReg})
+	tw.Write([]byte("data"))
+	tw.WriteHeader(&tar.Header{Name: "seed/passwd", Linkname: "/etc/passwd", Typeflag: tar.TypeSymlink})
+	tw.Close()
+	c := testServerClient(t, tarExecHandler(t, archive.Bytes()))
+
+	dir, err := ioutil.TempDir("", "klient-copy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// The copy does not finish if the padding is not read
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	skipped, err := c.CopyFromPod(ctx, "test", "web", "", "/data/seed", filepath.Join(dir, "seed"))
+	if err != nil {
+		t.Fatalf("Client.CopyFromPod() error = %v", err)
+	}
+	if want := []string{"seed/passwd"}; !reflect.DeepEqual(skipped, want) {
+		t.Errorf("Client.CopyFromPod() skipped = %v, want %v", skipped, want)
+	}
+	content, err := ioutil.ReadFile(filepath.Join(dir, "seed", "data.txt"))
+	if err != nil || strings.TrimSpace(string(content)) != "data" {
+		t.Errorf("Client.CopyFromPod() content = %q, %v, want %q", content, err, "data")
+	}
+}
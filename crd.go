@@ -0,0 +1,163 @@
+package klient
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+// DefaultCRDTimeout is the time to wait for the installed CRDs to be
+// established
+const DefaultCRDTimeout = time.Minute
+
+// crdGroupKind is the GroupKind of the CustomResourceDefinitions, in
+// apiextensions.k8s.io/v1 and v1beta1
+var crdGroupKind = schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}
+
+// InstallCRDs applies the CustomResourceDefinitions in the given content and
+// waits for them to be established and their names accepted. Then the
+// discovery cache is refreshed, so the custom resources can be applied right
+// after. It fails if the content has objects other than CRDs
+func (c *Client) InstallCRDs(content []byte) error {
+	r := c.ResultForContent(content, nil)
+	if err := r.Err(); err != nil {
+		return err
+	}
+	if err := c.visit(r, c.applier(), onlyCRDs, c.enforcePolicy); err != nil {
+		return err
+	}
+	if c.DryRun {
+		return nil
+	}
+
+	infos, err := r.Infos()
+	if err != nil {
+		return err
+	}
+	deadline := time.Now().Add(DefaultCRDTimeout)
+	errs := []error{}
+	for _, info := range infos {
+		if err := waitForCondition(info, crdEstablished, deadline); err != nil {
+			errs = append(errs, fmt.Errorf("the CRD %q is not established. %s", info.Name, err))
+		}
+	}
+	if len(errs) != 0 {
+		return utilerrors.NewAggregate(errs)
+	}
+
+	return c.factory.refreshDiscovery()
+}
+
+// UninstallCRDs deletes the CustomResourceDefinitions in the given content,
+// which also deletes their custom resources. If failIfInUse is true, nothing
+// is deleted if any of the CRDs still has custom resources. It fails if the
+// content has objects other than CRDs
+func (c *Client) UninstallCRDs(content []byte, failIfInUse bool) error {
+	r := c.ResultForContent(content, nil)
+	if err := r.Err(); err != nil {
+		return err
+	}
+	hooks := []visitHook{onlyCRDs}
+	if failIfInUse {
+		hooks = append(hooks, c.crdsNotInUse)
+	}
+	if err := c.visit(r, delete, hooks...); err != nil {
+		return err
+	}
+	if c.DryRun {
+		return nil
+	}
+
+	return c.factory.refreshDiscovery()
+}
+
+// onlyCRDs fails if any of the objects is not a CustomResourceDefinition
+func onlyCRDs(infos []*resource.Info) error {
+	errs := []error{}
+	for _, info := range infos {
+		if info.Mapping.GroupVersionKind.GroupKind() != crdGroupKind {
+			errs = append(errs, failedTo("accept", info, fmt.Errorf("it's not a CustomResourceDefinition")))
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// crdEstablished is the condition met when the CRD is established and its
+// names are accepted
+func crdEstablished(info *resource.Info, obj runtime.Object) (bool, string) {
+	if obj == nil {
+		return false, "the object was not found"
+	}
+	for _, condition := range []string{"Established", "NamesAccepted"} {
+		if met, reason := hasCondition(obj, condition, "True"); !met {
+			return false, reason
+		}
+	}
+	return true, ""
+}
+
+// crdsNotInUse fails if any of the CRDs has custom resources in any namespace
+func (c *Client) crdsNotInUse(infos []*resource.Info) error {
+	dyn, err := c.factory.DynamicClient()
+	if err != nil {
+		return err
+	}
+	errs := []error{}
+	for _, info := range infos {
+		gvr, err := crdResource(info.Object)
+		if err != nil {
+			errs = append(errs, failedTo("inspect", info, err))
+			continue
+		}
+		list, err := dyn.Resource(gvr).List(metav1.ListOptions{Limit: 1})
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, failedTo("list the custom resources of", info, err))
+			continue
+		}
+		if len(list.Items) != 0 {
+			errs = append(errs, failedTo("delete", info, fmt.Errorf("there are %s in use, i.e. %q", gvr.Resource, list.Items[0].GetName())))
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// crdResource returns the resource of the custom resources defined by the CRD,
+// with the first served version
+func crdResource(obj runtime.Object) (schema.GroupVersionResource, error) {
+	content := unstructuredContent(obj)
+	group, _, _ := unstructured.NestedString(content, "spec", "group")
+	plural, _, _ := unstructured.NestedString(content, "spec", "names", "plural")
+	if group == "" || plural == "" {
+		return schema.GroupVersionResource{}, fmt.Errorf("the group or the plural name is not defined")
+	}
+
+	// apiextensions.k8s.io/v1beta1 may only define spec.version
+	version, _, _ := unstructured.NestedString(content, "spec", "version")
+	versions, _, _ := unstructured.NestedSlice(content, "spec", "versions")
+	for _, v := range versions {
+		v, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if served, found, _ := unstructured.NestedBool(v, "served"); found && !served {
+			continue
+		}
+		version, _, _ = unstructured.NestedString(v, "name")
+		break
+	}
+	if version == "" {
+		return schema.GroupVersionResource{}, fmt.Errorf("there is no served version")
+	}
+
+	return schema.GroupVersionResource{Group: group, Version: version, Resource: plural}, nil
+}
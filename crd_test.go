@@ -0,0 +1,114 @@
+package klient
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+const (
+	crdV1 = `{"apiVersion": "apiextensions.k8s.io/v1", "kind": "CustomResourceDefinition", "metadata": {"name": "crontabs.stable.example.com"},
+		"spec": {"group": "stable.example.com", "names": {"plural": "crontabs", "kind": "CronTab"}, "scope": "Namespaced",
+			"versions": [{"name": "v1alpha1", "served": false}, {"name": "v1", "served": true, "storage": true}]}}`
+	crdV1beta1 = `{"apiVersion": "apiextensions.k8s.io/v1beta1", "kind": "CustomResourceDefinition", "metadata": {"name": "backups.stable.example.com"},
+		"spec": {"group": "stable.example.com", "version": "v1beta1", "names": {"plural": "backups", "kind": "Backup"}, "scope": "Namespaced"}}`
+)
+
+func TestCRDResource(t *testing.T) {
+	tests := []struct {
+		name    string
+		crd     string
+		want    schema.GroupVersionResource
+		wantErr bool
+	}{
+		{"v1", crdV1, schema.GroupVersionResource{Group: "stable.example.com", Version: "v1", Resource: "crontabs"}, false},
+		{"v1beta1", crdV1beta1, schema.GroupVersionResource{Group: "stable.example.com", Version: "v1beta1", Resource: "backups"}, false},
+		{"no group", `{"apiVersion": "apiextensions.k8s.io/v1", "kind": "CustomResourceDefinition", "metadata": {"name": "crontabs"}, "spec": {"names": {"plural": "crontabs"}}}`, schema.GroupVersionResource{}, true},
+		{"no served version", strings.Replace(crdV1, `"served": true`, `"served": false`, 1), schema.GroupVersionResource{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := crdResource(testInfo(t, tt.crd).Object)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("crdResource() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("crdResource() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCRDEstablished(t *testing.T) {
+	tests := []struct {
+		name       string
+		conditions string
+		want       bool
+	}{
+		{"established", `[{"type": "NamesAccepted", "status": "True"}, {"type": "Established", "status": "True"}]`, true},
+		{"names not accepted", `[{"type": "NamesAccepted", "status": "False"}, {"type": "Established", "status": "True"}]`, false},
+		{"not established", `[{"type": "NamesAccepted", "status": "True"}]`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := testInfo(t, strings.TrimSuffix(crdV1, "}")+`, "status": {"conditions": `+tt.conditions+`}}`)
+			if got, reason := crdEstablished(info, info.Object); got != tt.want {
+				t.Errorf("crdEstablished() = %v (%s), want %v", got, reason, tt.want)
+			}
+		})
+	}
+}
+
+func TestOnlyCRDs(t *testing.T) {
+	crd := testInfo(t, crdV1)
+	cm := testInfo(t, `{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "web", "namespace": "test"}}`)
+	if err := onlyCRDs([]*resource.Info{crd, testInfo(t, crdV1beta1)}); err != nil {
+		t.Errorf("onlyCRDs() error = %v, want nil", err)
+	}
+	if err := onlyCRDs([]*resource.Info{crd, cm}); err == nil || !strings.Contains(err.Error(), "ConfigMap") {
+		t.Errorf("onlyCRDs() error = %v, want the ConfigMap rejected", err)
+	}
+}
+
+func TestClient_crdsNotInUse(t *testing.T) {
+	c := testServerClient(t, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch req.URL.Path {
+		case "/apis/stable.example.com/v1/crontabs":
+			w.Write([]byte(`{"apiVersion": "stable.example.com/v1", "kind": "CronTabList", "metadata": {},
+				"items": [{"apiVersion": "stable.example.com/v1", "kind": "CronTab", "metadata": {"name": "daily", "namespace": "test"}}]}`))
+		case "/apis/stable.example.com/v1beta1/backups":
+			w.Write([]byte(`{"apiVersion": "stable.example.com/v1beta1", "kind": "BackupList", "metadata": {}, "items": []}`))
+		default:
+			http.NotFound(w, req)
+		}
+	}))
+
+	tests := []struct {
+		name    string
+		crds    []string
+		wantErr bool
+	}{
+		{"in use", []string{crdV1, crdV1beta1}, true},
+		{"not in use", []string{crdV1beta1}, false},
+		{"not installed", []string{strings.Replace(crdV1beta1, "backups", "restores", -1)}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			infos := []*resource.Info{}
+			for _, crd := range tt.crds {
+				infos = append(infos, testInfo(t, crd))
+			}
+			err := c.crdsNotInUse(infos)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.crdsNotInUse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), `"daily"`) {
+				t.Errorf("Client.crdsNotInUse() error = %v, want the custom resource in use", err)
+			}
+		})
+	}
+}
@@ -22,7 +22,7 @@ func (c *Client) CreateResource(r *resource.Result) error {
 	if err := r.Err(); err != nil {
 		return err
 	}
-	return r.Visit(create)
+	return c.visit(r, create, c.enforcePolicy, c.createNamespace)
 }
 
 func create(info *resource.Info, err error) error {
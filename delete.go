@@ -30,7 +30,7 @@ func (c *Client) DeleteResource(r *resource.Result) error {
 	if err := r.Err(); err != nil {
 		return err
 	}
-	return r.Visit(delete)
+	return c.visit(r, delete)
 }
 
 func delete(info *resource.Info, err error) error {
@@ -0,0 +1,239 @@
+package klient
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilversion "k8s.io/apimachinery/pkg/util/version"
+)
+
+// APIDeprecation is an object of the manifests using a deprecated or removed
+// API in the target Kubernetes version
+type APIDeprecation struct {
+	APIVersion string
+	Kind       string
+	Namespace  string
+	Name       string
+	// DeprecatedIn and RemovedIn are the Kubernetes versions the API is
+	// deprecated and removed, i.e. `1.16`
+	DeprecatedIn string
+	RemovedIn    string
+	// Replacement is the apiVersion to use instead, empty if there is none
+	Replacement string
+	// Removed is true if the API is removed, or not served, in the target version
+	Removed bool
+}
+
+func (d APIDeprecation) String() string {
+	name := d.Name
+	if d.Namespace != "" {
+		name = d.Namespace + "/" + d.Name
+	}
+	status := fmt.Sprintf("is deprecated in v%s and removed in v%s", d.DeprecatedIn, d.RemovedIn)
+	if d.Removed {
+		status = fmt.Sprintf("is removed or not served (deprecated in v%s, removed in v%s)", d.DeprecatedIn, d.RemovedIn)
+	}
+	replacement := ", there is no replacement"
+	if d.Replacement != "" {
+		replacement = ", use " + d.Replacement
+	}
+	return fmt.Sprintf("%s %s: %s %s%s", d.Kind, name, d.APIVersion, status, replacement)
+}
+
+// DeprecationError is the error of the manifests with objects using APIs
+// removed in the target Kubernetes version
+type DeprecationError struct {
+	Removed []APIDeprecation
+}
+
+func (e *DeprecationError) Error() string {
+	msgs := make([]string, 0, len(e.Removed))
+	for _, d := range e.Removed {
+		msgs = append(msgs, d.String())
+	}
+	return fmt.Sprintf("the objects use removed APIs. %s", strings.Join(msgs, "; "))
+}
+
+// apiDeprecation is a deprecated API of the kinds in the apiVersion
+type apiDeprecation struct {
+	apiVersion   string
+	kinds        []string
+	deprecatedIn string
+	removedIn    string
+	replacement  string
+}
+
+// apiDeprecations are the deprecations of the Kubernetes APIs
+var apiDeprecations = []apiDeprecation{
+	{"extensions/v1beta1", []string{"Deployment", "DaemonSet", "ReplicaSet"}, "1.8", "1.16", "apps/v1"},
+	{"extensions/v1beta1", []string{"NetworkPolicy"}, "1.9", "1.16", "networking.k8s.io/v1"},
+	{"extensions/v1beta1", []string{"PodSecurityPolicy"}, "1.11", "1.16", "policy/v1beta1"},
+	{"extensions/v1beta1", []string{"Ingress"}, "1.14", "1.22", "networking.k8s.io/v1"},
+	{"apps/v1beta1", []string{"Deployment", "StatefulSet"}, "1.9", "1.16", "apps/v1"},
+	{"apps/v1beta2", []string{"Deployment", "DaemonSet", "ReplicaSet", "StatefulSet"}, "1.9", "1.16", "apps/v1"},
+	{"networking.k8s.io/v1beta1", []string{"Ingress", "IngressClass"}, "1.19", "1.22", "networking.k8s.io/v1"},
+	{"rbac.authorization.k8s.io/v1alpha1", []string{"Role", "ClusterRole", "RoleBinding", "ClusterRoleBinding"}, "1.17", "1.22", "rbac.authorization.k8s.io/v1"},
+	{"rbac.authorization.k8s.io/v1beta1", []string{"Role", "ClusterRole", "RoleBinding", "ClusterRoleBinding"}, "1.17", "1.22", "rbac.authorization.k8s.io/v1"},
+	{"apiextensions.k8s.io/v1beta1", []string{"CustomResourceDefinition"}, "1.16", "1.22", "apiextensions.k8s.io/v1"},
+	{"admissionregistration.k8s.io/v1beta1", []string{"MutatingWebhookConfiguration", "ValidatingWebhookConfiguration"}, "1.16", "1.22", "admissionregistration.k8s.io/v1"},
+	{"apiregistration.k8s.io/v1beta1", []string{"APIService"}, "1.19", "1.22", "apiregistration.k8s.io/v1"},
+	{"scheduling.k8s.io/v1beta1", []string{"PriorityClass"}, "1.14", "1.22", "scheduling.k8s.io/v1"},
+	{"storage.k8s.io/v1beta1", []string{"StorageClass", "VolumeAttachment", "CSIDriver", "CSINode"}, "1.19", "1.22", "storage.k8s.io/v1"},
+	{"storage.k8s.io/v1beta1", []string{"CSIStorageCapacity"}, "1.24", "1.27", "storage.k8s.io/v1"},
+	{"certificates.k8s.io/v1beta1", []string{"CertificateSigningRequest"}, "1.19", "1.22", "certificates.k8s.io/v1"},
+	{"coordination.k8s.io/v1beta1", []string{"Lease"}, "1.19", "1.22", "coordination.k8s.io/v1"},
+	{"batch/v1beta1", []string{"CronJob"}, "1.21", "1.25", "batch/v1"},
+	{"policy/v1beta1", []string{"PodDisruptionBudget"}, "1.21", "1.25", "policy/v1"},
+	{"policy/v1beta1", []string{"PodSecurityPolicy"}, "1.21", "1.25", ""},
+	{"discovery.k8s.io/v1beta1", []string{"EndpointSlice"}, "1.21", "1.25", "discovery.k8s.io/v1"},
+	{"events.k8s.io/v1beta1", []string{"Event"}, "1.21", "1.25", "events.k8s.io/v1"},
+	{"node.k8s.io/v1beta1", []string{"RuntimeClass"}, "1.22", "1.25", "node.k8s.io/v1"},
+	{"autoscaling/v2beta1", []string{"HorizontalPodAutoscaler"}, "1.22", "1.25", "autoscaling/v2"},
+	{"autoscaling/v2beta2", []string{"HorizontalPodAutoscaler"}, "1.23", "1.26", "autoscaling/v2"},
+	{"flowcontrol.apiserver.k8s.io/v1beta1", []string{"FlowSchema", "PriorityLevelConfiguration"}, "1.23", "1.26", "flowcontrol.apiserver.k8s.io/v1"},
+	{"flowcontrol.apiserver.k8s.io/v1beta2", []string{"FlowSchema", "PriorityLevelConfiguration"}, "1.26", "1.29", "flowcontrol.apiserver.k8s.io/v1"},
+	{"flowcontrol.apiserver.k8s.io/v1beta3", []string{"FlowSchema", "PriorityLevelConfiguration"}, "1.29", "1.32", "flowcontrol.apiserver.k8s.io/v1"},
+}
+
+// FindDeprecations returns the objects in the manifests using APIs deprecated
+// or removed in the target Kubernetes version, i.e. `1.22` or `v1.22.3`. It
+// uses a built-in table of the Kubernetes deprecations, so a cluster is not
+// required. Use it to verify the manifests before a cluster upgrade
+func FindDeprecations(content []byte, targetVersion string) ([]APIDeprecation, error) {
+	target, err := utilversion.ParseGeneric(targetVersion)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse the Kubernetes version %q. %s", targetVersion, err)
+	}
+	return findDeprecations(content, target, nil)
+}
+
+// FindDeprecations returns the objects in the manifests using APIs deprecated
+// or removed in the version of the cluster. The APIs in the table of
+// deprecations that the cluster does not serve are considered removed
+func (c *Client) FindDeprecations(content []byte) ([]APIDeprecation, error) {
+	dc, err := c.factory.ToDiscoveryClient()
+	if err != nil {
+		return nil, err
+	}
+	info, err := dc.ServerVersion()
+	if err != nil {
+		return nil, fmt.Errorf("cannot get the server version. %s", err)
+	}
+	target, err := utilversion.ParseGeneric(info.GitVersion)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse the server version %q. %s", info.GitVersion, err)
+	}
+
+	served := map[string]map[string]bool{}
+	isServed := func(apiVersion, kind string) (bool, error) {
+		if kinds, ok := served[apiVersion]; ok {
+			return kinds[kind], nil
+		}
+		served[apiVersion] = map[string]bool{}
+		list, err := dc.ServerResourcesForGroupVersion(apiVersion)
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("cannot discover the resources of %s. %s", apiVersion, err)
+		}
+		for _, r := range list.APIResources {
+			if !strings.Contains(r.Name, "/") {
+				served[apiVersion][r.Kind] = true
+			}
+		}
+		return served[apiVersion][kind], nil
+	}
+
+	return findDeprecations(content, target, isServed)
+}
+
+// checkDeprecations reports with WarnDeprecations the objects in the manifests
+// using deprecated APIs and fails if any of them is removed in the cluster
+// version
+func (c *Client) checkDeprecations(content []byte) error {
+	deprecations, err := c.FindDeprecations(content)
+	if err != nil {
+		return err
+	}
+	var removed, deprecated []APIDeprecation
+	for _, d := range deprecations {
+		if d.Removed {
+			removed = append(removed, d)
+			continue
+		}
+		deprecated = append(deprecated, d)
+	}
+	if len(deprecated) != 0 && c.WarnDeprecations != nil {
+		c.WarnDeprecations(deprecated)
+	}
+	if len(removed) != 0 {
+		return &DeprecationError{Removed: removed}
+	}
+	return nil
+}
+
+// findDeprecations returns the objects using APIs deprecated in the target
+// version. If isServed is set, the APIs not served are considered removed
+func findDeprecations(content []byte, target *utilversion.Version, isServed func(apiVersion, kind string) (bool, error)) ([]APIDeprecation, error) {
+	docs, err := decodeDocuments(content)
+	if err != nil {
+		return nil, err
+	}
+
+	var objs []map[string]interface{}
+	for _, doc := range docs {
+		if doc.obj == nil {
+			continue
+		}
+		objs = append(objs, doc.obj)
+		if items, ok := doc.obj["items"].([]interface{}); ok && strings.HasSuffix(doc.gvk.Kind, "List") {
+			for _, item := range items {
+				if obj, ok := item.(map[string]interface{}); ok {
+					objs = append(objs, obj)
+				}
+			}
+		}
+	}
+
+	var deprecations []APIDeprecation
+	for _, obj := range objs {
+		u := &unstructured.Unstructured{Object: obj}
+		dep := lookupDeprecation(u.GetAPIVersion(), u.GetKind())
+		if dep == nil || !target.AtLeast(utilversion.MustParseGeneric(dep.deprecatedIn)) {
+			continue
+		}
+		d := APIDeprecation{
+			APIVersion:   u.GetAPIVersion(),
+			Kind:         u.GetKind(),
+			Namespace:    u.GetNamespace(),
+			Name:         u.GetName(),
+			DeprecatedIn: dep.deprecatedIn,
+			RemovedIn:    dep.removedIn,
+			Replacement:  dep.replacement,
+			Removed:      target.AtLeast(utilversion.MustParseGeneric(dep.removedIn)),
+		}
+		if !d.Removed && isServed != nil {
+			served, err := isServed(d.APIVersion, d.Kind)
+			if err != nil {
+				return nil, err
+			}
+			d.Removed = !served
+		}
+		deprecations = append(deprecations, d)
+	}
+	return deprecations, nil
+}
+
+// lookupDeprecation returns the deprecation of the kind in the apiVersion, nil
+// if it's not deprecated
+func lookupDeprecation(apiVersion, kind string) *apiDeprecation {
+	for i, dep := range apiDeprecations {
+		if dep.apiVersion == apiVersion && containsString(dep.kinds, kind) {
+			return &apiDeprecations[i]
+		}
+	}
+	return nil
+}
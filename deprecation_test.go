@@ -0,0 +1,119 @@
+package klient
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/version"
+)
+
+const deprecatedManifests = `apiVersion: extensions/v1beta1
+kind: Ingress
+metadata:
+  name: web
+  namespace: apps
+---
+apiVersion: v1
+kind: List
+items:
+- apiVersion: apps/v1beta2
+  kind: Deployment
+  metadata:
+    name: web
+    namespace: apps
+- apiVersion: batch/v1beta1
+  kind: CronJob
+  metadata:
+    name: backup
+    namespace: apps
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: web
+`
+
+func TestFindDeprecations(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		want    []string
+		wantErr bool
+	}{
+		{"old cluster", "1.13", []string{"Deployment apps/web: apps/v1beta2 is deprecated in v1.9 and removed in v1.16, use apps/v1"}, false},
+		{"deprecated", "v1.15.3", []string{
+			"Ingress apps/web: extensions/v1beta1 is deprecated in v1.14 and removed in v1.22, use networking.k8s.io/v1",
+			"Deployment apps/web: apps/v1beta2 is deprecated in v1.9 and removed in v1.16, use apps/v1",
+		}, false},
+		{"removed", "1.25", []string{
+			"Ingress apps/web: extensions/v1beta1 is removed or not served (deprecated in v1.14, removed in v1.22), use networking.k8s.io/v1",
+			"Deployment apps/web: apps/v1beta2 is removed or not served (deprecated in v1.9, removed in v1.16), use apps/v1",
+			"CronJob apps/backup: batch/v1beta1 is removed or not served (deprecated in v1.21, removed in v1.25), use batch/v1",
+		}, false},
+		{"invalid version", "latest", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deprecations, err := FindDeprecations([]byte(deprecatedManifests), tt.version)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FindDeprecations() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var got []string
+			for _, d := range deprecations {
+				got = append(got, d.String())
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("FindDeprecations() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_checkDeprecations(t *testing.T) {
+	// The cluster serves only the Ingress of extensions/v1beta1
+	responses := map[string]interface{}{
+		"/version": version.Info{Major: "1", Minor: "15", GitVersion: "v1.15.0"},
+		"/apis/extensions/v1beta1": metav1.APIResourceList{GroupVersion: "extensions/v1beta1", APIResources: []metav1.APIResource{
+			{Name: "ingresses", Kind: "Ingress", Namespaced: true},
+		}},
+	}
+	c := testServerClient(t, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		resp, ok := responses[req.URL.Path]
+		if !ok {
+			http.NotFound(w, req)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+
+	var warned []APIDeprecation
+	c.WarnDeprecations = func(deprecations []APIDeprecation) {
+		warned = append(warned, deprecations...)
+	}
+
+	err := c.checkDeprecations([]byte(deprecatedManifests))
+	var derr *DeprecationError
+	if !errors.As(err, &derr) {
+		t.Fatalf("Client.checkDeprecations() error = %v, want a DeprecationError", err)
+	}
+	if len(derr.Removed) != 1 || derr.Removed[0].Kind != "Deployment" {
+		t.Errorf("Client.checkDeprecations() removed = %v, want the Deployment", derr.Removed)
+	}
+
+	if len(warned) != 1 || warned[0].Kind != "Ingress" {
+		t.Errorf("Client.checkDeprecations() warned = %v, want the Ingress", warned)
+	}
+
+	warned = nil
+	if err := c.checkDeprecations([]byte(deprecatedManifests[:strings.Index(deprecatedManifests, "---")])); err != nil {
+		t.Errorf("Client.checkDeprecations() error = %v, want only warnings", err)
+	}
+	if len(warned) != 1 {
+		t.Errorf("Client.checkDeprecations() warned = %v, want 1 warning", warned)
+	}
+}
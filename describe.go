@@ -0,0 +1,67 @@
+package klient
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/kubectl/pkg/describe"
+	"k8s.io/kubectl/pkg/describe/versioned"
+)
+
+// Description is the structured description of an object: the object as it's
+// in the cluster and its events
+type Description struct {
+	Object *unstructured.Unstructured
+	Events []corev1.Event
+}
+
+// Describe returns the description of the object of the given kind, or
+// resource, and name in the client namespace, like `kubectl describe`
+func (c *Client) Describe(kind, name string) (string, error) {
+	mapping, err := c.mappingFor(kind)
+	if err != nil {
+		return "", err
+	}
+	describer, err := versioned.Describer(c.factory, mapping)
+	if err != nil {
+		return "", fmt.Errorf("cannot describe %s %q. %s", kind, name, err)
+	}
+
+	namespace := c.namespace
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		namespace = ""
+	}
+	return describer.Describe(namespace, name, describe.DescriberSettings{ShowEvents: true})
+}
+
+// DescribeObject returns the structured description of the object of the
+// given kind, or resource, and name in the client namespace
+func (c *Client) DescribeObject(kind, name string) (*Description, error) {
+	mapping, err := c.mappingFor(kind)
+	if err != nil {
+		return nil, err
+	}
+	ri, _, err := c.resourceInterface(mapping.GroupVersionKind, c.namespace)
+	if err != nil {
+		return nil, err
+	}
+	obj, err := ri.Get(name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("cannot describe %s %q. %s", kind, name, err)
+	}
+
+	// The events of cluster-scoped objects are in the default namespace
+	namespace := obj.GetNamespace()
+	if namespace == "" {
+		namespace = corev1.NamespaceDefault
+	}
+	events, err := c.listEvents(namespace, string(obj.GetUID()))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Description{Object: obj, Events: events}, nil
+}
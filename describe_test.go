@@ -0,0 +1,54 @@
+package klient
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestClient_Describe(t *testing.T) {
+	envContext := os.Getenv(contextEnvVarName)
+	envKubeconfig := os.Getenv(kubeconfigEnvVarName)
+
+	c, err := NewE(envContext, envKubeconfig)
+	if err != nil {
+		t.Fatalf("failed to create the client with context %q and kubeconfig %q", envContext, envKubeconfig)
+	}
+
+	cm := []byte(`{"apiVersion": "v1", "kind": "ConfigMap", "metadata": { "name": "test-describe-0" }, "data": { "key1": "apple" } }`)
+	if err := c.Apply(cm); err != nil {
+		t.Fatalf("Client.Apply() error = %v", err)
+	}
+	defer c.Delete(cm)
+
+	tests := []struct {
+		name    string
+		kind    string
+		objName string
+		wantErr bool
+	}{
+		{"kind", "ConfigMap", "test-describe-0", false},
+		{"resource", "configmaps", "test-describe-0", false},
+		{"not found", "configmap", "test-describe-1", true},
+		{"unknown kind", "foo", "test-describe-0", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.Describe(tt.kind, tt.objName)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Client.Describe() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (!strings.Contains(got, tt.objName) || !strings.Contains(got, "apple")) {
+				t.Errorf("Client.Describe() = %q, want the description of %q", got, tt.objName)
+			}
+
+			desc, err := c.DescribeObject(tt.kind, tt.objName)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Client.DescribeObject() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && desc.Object.GetName() != tt.objName {
+				t.Errorf("Client.DescribeObject() object = %q, want %q", desc.Object.GetName(), tt.objName)
+			}
+		})
+	}
+}
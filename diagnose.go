@@ -0,0 +1,440 @@
+package klient
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+// Diagnostic is the information to find out why an object failed: its
+// conditions, events and, for workloads, the status of its pods
+type Diagnostic struct {
+	Object     ObjectReference
+	Conditions []DiagnosticCondition
+	Events     []DiagnosticEvent
+	Pods       []PodDiagnostic
+}
+
+// DiagnosticCondition is a condition of the object status
+type DiagnosticCondition struct {
+	Type    string
+	Status  string
+	Reason  string
+	Message string
+}
+
+// DiagnosticEvent is an event related to the object
+type DiagnosticEvent struct {
+	Type    string
+	Reason  string
+	Message string
+	Count   int32
+}
+
+// PodDiagnostic is the status of a pod owned by the object
+type PodDiagnostic struct {
+	Name       string
+	Phase      corev1.PodPhase
+	Conditions []DiagnosticCondition
+	Containers []ContainerDiagnostic
+	Events     []DiagnosticEvent
+}
+
+// ContainerDiagnostic is the status of a container. State is `waiting`,
+// `running` or `terminated` with the Reason, i.e. `CrashLoopBackOff` or
+// `ImagePullBackOff`. LastTerminationReason, i.e. `OOMKilled` or `Error`, and
+// ExitCode are of the last time the container was terminated
+type ContainerDiagnostic struct {
+	Name                  string
+	Ready                 bool
+	Restarts              int32
+	State                 string
+	Reason                string
+	Message               string
+	LastTerminationReason string
+	ExitCode              int32
+}
+
+// Diagnostics is the diagnostic of a set of objects
+type Diagnostics []Diagnostic
+
+// DiagnosticError is an error with the diagnostics of the failed objects
+type DiagnosticError struct {
+	Err         error
+	Diagnostics Diagnostics
+}
+
+func (e *DiagnosticError) Error() string {
+	return fmt.Sprintf("%s\n%s", e.Err, e.Diagnostics)
+}
+
+func (e *DiagnosticError) Unwrap() error { return e.Err }
+
+// Diagnose returns the diagnostic of the object of the given kind, or
+// resource, and name in the client namespace
+func (c *Client) Diagnose(kind, name string) (*Diagnostic, error) {
+	info, err := c.object(kind, name)
+	if err != nil {
+		return nil, err
+	}
+	return c.diagnose(info)
+}
+
+// String returns the human-readable summary of the problems found: the
+// failing conditions, the warning events and the failing pods
+func (d Diagnostics) String() string {
+	var b strings.Builder
+	for _, diag := range d {
+		if !diag.hasFindings() {
+			continue
+		}
+		fmt.Fprintf(&b, "%s:\n", diag.Object)
+		for _, cond := range diag.Conditions {
+			if cond.failing() {
+				fmt.Fprintf(&b, "  condition %s is %s: %s %s\n", cond.Type, cond.Status, cond.Reason, cond.Message)
+			}
+		}
+		writeWarnings(&b, "  ", diag.Events)
+		for _, pod := range diag.Pods {
+			if !pod.failing() {
+				continue
+			}
+			fmt.Fprintf(&b, "  pod %s is %s\n", pod.Name, pod.Phase)
+			for _, container := range pod.Containers {
+				if !container.failing() {
+					continue
+				}
+				fmt.Fprintf(&b, "    container %s is %s", container.Name, container.State)
+				if container.Reason != "" {
+					fmt.Fprintf(&b, " (%s)", container.Reason)
+				}
+				if container.LastTerminationReason != "" {
+					fmt.Fprintf(&b, ", last terminated with %s", container.LastTerminationReason)
+				}
+				fmt.Fprintf(&b, ", exit code %d, %d restarts", container.ExitCode, container.Restarts)
+				if container.Message != "" {
+					fmt.Fprintf(&b, ": %s", container.Message)
+				}
+				b.WriteString("\n")
+			}
+			writeWarnings(&b, "    ", pod.Events)
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func writeWarnings(b *strings.Builder, indent string, events []DiagnosticEvent) {
+	for _, event := range events {
+		if event.Type == corev1.EventTypeWarning {
+			fmt.Fprintf(b, "%sevent %s (x%d): %s\n", indent, event.Reason, event.Count, event.Message)
+		}
+	}
+}
+
+func (d Diagnostics) hasFindings() bool {
+	for _, diag := range d {
+		if diag.hasFindings() {
+			return true
+		}
+	}
+	return false
+}
+
+func (d Diagnostic) hasFindings() bool {
+	for _, cond := range d.Conditions {
+		if cond.failing() {
+			return true
+		}
+	}
+	for _, event := range d.Events {
+		if event.Type == corev1.EventTypeWarning {
+			return true
+		}
+	}
+	for _, pod := range d.Pods {
+		if pod.failing() {
+			return true
+		}
+	}
+	return false
+}
+
+// failing returns true if the condition reports a problem. Most of the
+// conditions are positive, except the failure and pressure ones
+func (c DiagnosticCondition) failing() bool {
+	negative := strings.Contains(c.Type, "Failure") || strings.Contains(c.Type, "Pressure") || c.Type == "Failed"
+	if negative {
+		return c.Status == string(corev1.ConditionTrue)
+	}
+	return c.Status == string(corev1.ConditionFalse)
+}
+
+func (p PodDiagnostic) failing() bool {
+	if p.Phase == corev1.PodFailed || p.Phase == corev1.PodPending {
+		return true
+	}
+	for _, container := range p.Containers {
+		if container.failing() {
+			return true
+		}
+	}
+	return false
+}
+
+func (c ContainerDiagnostic) failing() bool {
+	switch c.State {
+	case "waiting":
+		return c.Reason != "" && c.Reason != "ContainerCreating" && c.Reason != "PodInitializing"
+	case "terminated":
+		return c.ExitCode != 0
+	}
+	if c.LastTerminationReason == "OOMKilled" {
+		return true
+	}
+	return !c.Ready && c.Restarts > 0
+}
+
+// withDiagnostics returns the error with the diagnostics of the objects, if
+// any problem is found
+func (c *Client) withDiagnostics(err error, infos []*resource.Info) error {
+	if err == nil || len(infos) == 0 {
+		return err
+	}
+	if _, ok := err.(*DiagnosticError); ok {
+		return err
+	}
+
+	var diags Diagnostics
+	for _, info := range infos {
+		diag, derr := c.diagnose(info)
+		if derr != nil || diag == nil {
+			continue
+		}
+		diags = append(diags, *diag)
+	}
+	if !diags.hasFindings() {
+		return err
+	}
+	return &DiagnosticError{Err: err, Diagnostics: diags}
+}
+
+// diagnose returns the diagnostic of the object, or nil if it does not exists
+func (c *Client) diagnose(info *resource.Info) (*Diagnostic, error) {
+	current, err := resource.NewHelper(info.Client, info.Mapping).Get(info.Namespace, info.Name, false)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, failedTo("retrieve current status", info, err)
+	}
+	obj := unstructuredContent(current)
+	accessor, err := meta.Accessor(current)
+	if err != nil {
+		return nil, err
+	}
+
+	diag := &Diagnostic{
+		Object:     objectReferences([]*resource.Info{info})[0],
+		Conditions: objectConditions(obj),
+	}
+	diag.Object.UID = accessor.GetUID()
+	if diag.Events, err = c.eventsFor(info.Namespace, string(accessor.GetUID())); err != nil {
+		return nil, err
+	}
+
+	var pods []corev1.Pod
+	if info.Mapping.GroupVersionKind.GroupKind() == corev1.SchemeGroupVersion.WithKind("Pod").GroupKind() {
+		var pod corev1.Pod
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj, &pod); err != nil {
+			return nil, failedTo("convert the pod status", info, err)
+		}
+		pods = []corev1.Pod{pod}
+	} else if info.Namespaced() && isWorkload(obj) {
+		if pods, err = c.podsControlledBy(info.Namespace, accessor.GetUID()); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, pod := range pods {
+		podDiag := podDiagnostic(pod)
+		if podDiag.failing() && pod.UID != accessor.GetUID() {
+			if podDiag.Events, err = c.eventsFor(pod.Namespace, string(pod.UID)); err != nil {
+				return nil, err
+			}
+		}
+		diag.Pods = append(diag.Pods, podDiag)
+	}
+
+	return diag, nil
+}
+
+// isWorkload returns true if the object creates pods from a template, like
+// the Deployments, StatefulSets, Jobs or CronJobs
+func isWorkload(obj map[string]interface{}) bool {
+	_, template, _ := unstructured.NestedMap(obj, "spec", "template")
+	_, jobTemplate, _ := unstructured.NestedMap(obj, "spec", "jobTemplate")
+	return template || jobTemplate
+}
+
+// podsControlledBy returns the pods in the namespace controlled by the object
+// with the given UID, directly or through a ReplicaSet or a Job, like the pods
+// of a Deployment or a CronJob. The pods are found by their owner references,
+// the pods of other objects matching the same labels are not included
+func (c *Client) podsControlledBy(namespace string, uid types.UID) ([]corev1.Pod, error) {
+	owners := map[types.UID]bool{uid: true}
+	replicaSets, err := c.Clientset.AppsV1().ReplicaSets(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, rs := range replicaSets.Items {
+		if controlledBy(&rs, owners) {
+			owners[rs.UID] = true
+		}
+	}
+	jobs, err := c.Clientset.BatchV1().Jobs(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, job := range jobs.Items {
+		if controlledBy(&job, owners) {
+			owners[job.UID] = true
+		}
+	}
+
+	list, err := c.Clientset.CoreV1().Pods(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var pods []corev1.Pod
+	for _, pod := range list.Items {
+		if controlledBy(&pod, owners) {
+			pods = append(pods, pod)
+		}
+	}
+	return pods, nil
+}
+
+// controlledBy returns true if the controller of the object is one of the owners
+func controlledBy(obj metav1.Object, owners map[types.UID]bool) bool {
+	ref := metav1.GetControllerOf(obj)
+	return ref != nil && owners[ref.UID]
+}
+
+// eventsFor returns the events of the object with the given UID, sorted by
+// the last time they happened
+func (c *Client) eventsFor(namespace, uid string) ([]DiagnosticEvent, error) {
+	list, err := c.listEvents(namespace, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]DiagnosticEvent, 0, len(list))
+	for _, e := range list {
+		events = append(events, DiagnosticEvent{
+			Type:    e.Type,
+			Reason:  e.Reason,
+			Message: e.Message,
+			Count:   e.Count,
+		})
+	}
+	return events, nil
+}
+
+// listEvents returns the events involving the object with the given UID,
+// sorted by the last time they happened
+func (c *Client) listEvents(namespace, uid string) ([]corev1.Event, error) {
+	list, err := c.Clientset.CoreV1().Events(namespace).List(metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("involvedObject.uid", uid).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(list.Items, func(i, j int) bool {
+		return list.Items[i].LastTimestamp.Before(&list.Items[j].LastTimestamp)
+	})
+	return list.Items, nil
+}
+
+func podDiagnostic(pod corev1.Pod) PodDiagnostic {
+	diag := PodDiagnostic{
+		Name:  pod.Name,
+		Phase: pod.Status.Phase,
+	}
+	for _, cond := range pod.Status.Conditions {
+		diag.Conditions = append(diag.Conditions, DiagnosticCondition{
+			Type:    string(cond.Type),
+			Status:  string(cond.Status),
+			Reason:  cond.Reason,
+			Message: cond.Message,
+		})
+	}
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		diag.Containers = append(diag.Containers, containerDiagnostic(status))
+	}
+	return diag
+}
+
+func containerDiagnostic(status corev1.ContainerStatus) ContainerDiagnostic {
+	diag := ContainerDiagnostic{
+		Name:     status.Name,
+		Ready:    status.Ready,
+		Restarts: status.RestartCount,
+	}
+	switch state := status.State; {
+	case state.Waiting != nil:
+		diag.State, diag.Reason, diag.Message = "waiting", state.Waiting.Reason, state.Waiting.Message
+	case state.Terminated != nil:
+		diag.State, diag.Reason, diag.Message = "terminated", state.Terminated.Reason, state.Terminated.Message
+		diag.ExitCode = state.Terminated.ExitCode
+	case state.Running != nil:
+		diag.State = "running"
+	}
+	// The reason of a crashing container is in the last termination
+	if last := status.LastTerminationState.Terminated; last != nil && diag.State != "terminated" {
+		diag.LastTerminationReason, diag.ExitCode = last.Reason, last.ExitCode
+	}
+	return diag
+}
+
+// objectConditions returns the conditions in the status of the object
+func objectConditions(obj map[string]interface{}) []DiagnosticCondition {
+	items, _, _ := unstructured.NestedSlice(obj, "status", "conditions")
+	var conditions []DiagnosticCondition
+	for _, item := range items {
+		cond, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		str := func(key string) string { s, _ := cond[key].(string); return s }
+		conditions = append(conditions, DiagnosticCondition{
+			Type:    str("type"),
+			Status:  str("status"),
+			Reason:  str("reason"),
+			Message: str("message"),
+		})
+	}
+	return conditions
+}
+
+// unstructuredContent returns the content of the object
+func unstructuredContent(obj runtime.Object) map[string]interface{} {
+	if u, ok := obj.(runtime.Unstructured); ok {
+		return u.UnstructuredContent()
+	}
+	m, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil
+	}
+	return m
+}
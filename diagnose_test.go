@@ -0,0 +1,153 @@
+package klient
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestContainerDiagnostic(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      corev1.ContainerStatus
+		wantState   string
+		wantReason  string
+		wantLast    string
+		wantFailing bool
+	}{
+		{"running", corev1.ContainerStatus{Name: "c", Ready: true, State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+			"running", "", "", false},
+		{"creating", corev1.ContainerStatus{Name: "c", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ContainerCreating"}}},
+			"waiting", "ContainerCreating", "", false},
+		{"image pull", corev1.ContainerStatus{Name: "c", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff", Message: "not found"}}},
+			"waiting", "ImagePullBackOff", "", true},
+		{"crash loop", corev1.ContainerStatus{Name: "c", RestartCount: 5,
+			State:                corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+			LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "Error", ExitCode: 1}}},
+			"waiting", "CrashLoopBackOff", "Error", true},
+		{"oom killed", corev1.ContainerStatus{Name: "c", Ready: true, RestartCount: 1,
+			State:                corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+			LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137}}},
+			"running", "", "OOMKilled", true},
+		{"completed", corev1.ContainerStatus{Name: "c", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "Completed"}}},
+			"terminated", "Completed", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := containerDiagnostic(tt.status)
+			if got.State != tt.wantState || got.Reason != tt.wantReason || got.LastTerminationReason != tt.wantLast {
+				t.Errorf("containerDiagnostic() = %+v, want state %q, reason %q and last termination %q", got, tt.wantState, tt.wantReason, tt.wantLast)
+			}
+			if got.failing() != tt.wantFailing {
+				t.Errorf("containerDiagnostic().failing() = %v, want %v", got.failing(), tt.wantFailing)
+			}
+		})
+	}
+}
+
+func TestDiagnostics_String(t *testing.T) {
+	diags := Diagnostics{
+		{
+			Object: ObjectReference{Group: "apps", Version: "v1", Kind: "Deployment", Namespace: "apps", Name: "web"},
+			Conditions: []DiagnosticCondition{
+				{Type: "Available", Status: "False", Reason: "MinimumReplicasUnavailable", Message: "Deployment does not have minimum availability."},
+				{Type: "Progressing", Status: "True", Reason: "ReplicaSetUpdated"},
+				{Type: "ReplicaFailure", Status: "False"},
+			},
+			Pods: []PodDiagnostic{
+				{Name: "web-1", Phase: corev1.PodRunning, Containers: []ContainerDiagnostic{
+					{Name: "nginx", State: "waiting", Reason: "CrashLoopBackOff", LastTerminationReason: "OOMKilled", ExitCode: 137, Restarts: 3},
+				}, Events: []DiagnosticEvent{{Type: "Warning", Reason: "BackOff", Message: "Back-off restarting failed container", Count: 3}}},
+				{Name: "web-2", Phase: corev1.PodRunning, Containers: []ContainerDiagnostic{{Name: "nginx", State: "running", Ready: true}}},
+			},
+		},
+		{
+			Object: ObjectReference{Version: "v1", Kind: "ConfigMap", Namespace: "apps", Name: "cm"},
+			Events: []DiagnosticEvent{{Type: "Normal", Reason: "Created"}},
+		},
+	}
+
+	want := `Deployment apps/web:
+  condition Available is False: MinimumReplicasUnavailable Deployment does not have minimum availability.
+  pod web-1 is Running
+    container nginx is waiting (CrashLoopBackOff), last terminated with OOMKilled, exit code 137, 3 restarts
+    event BackOff (x3): Back-off restarting failed container`
+	if got := diags.String(); got != want {
+		t.Errorf("Diagnostics.String() =\n%s\nwant\n%s", got, want)
+	}
+	if !diags.hasFindings() {
+		t.Errorf("Diagnostics.hasFindings() = false, want true")
+	}
+	if diags[1:].hasFindings() {
+		t.Errorf("Diagnostics.hasFindings() = true for an object without problems")
+	}
+	err := &DiagnosticError{Err: errors.New("apply failed"), Diagnostics: diags}
+	if !strings.HasPrefix(err.Error(), "apply failed\nDeployment apps/web:") {
+		t.Errorf("DiagnosticError.Error() = %q", err.Error())
+	}
+}
+
+func TestClient_diagnose_pods(t *testing.T) {
+	controller := func(kind string, uid types.UID) []metav1.OwnerReference {
+		yes := true
+		return []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: kind, Name: string(uid), UID: uid, Controller: &yes}}
+	}
+	labels := map[string]string{"app": "web"}
+	crashing := corev1.PodStatus{Phase: corev1.PodRunning, ContainerStatuses: []corev1.ContainerStatus{{
+		Name: "app", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+	}}}
+	// The pod `other` has the labels of the Deployment but it's owned by
+	// another ReplicaSet
+	responses := map[string]interface{}{
+		"/apis/apps/v1/namespaces/test/deployments/web": map[string]interface{}{
+			"apiVersion": "apps/v1", "kind": "Deployment",
+			"metadata": map[string]interface{}{"name": "web", "namespace": "test", "uid": "deploy"},
+			"spec": map[string]interface{}{
+				"selector": map[string]interface{}{"matchLabels": map[string]interface{}{"app": "web"}},
+				"template": map[string]interface{}{"metadata": map[string]interface{}{"labels": map[string]interface{}{"app": "web"}}},
+			},
+		},
+		"/apis/apps/v1/namespaces/test/replicasets": appsv1.ReplicaSetList{Items: []appsv1.ReplicaSet{
+			{ObjectMeta: metav1.ObjectMeta{Name: "web-1", UID: "rs", Labels: labels, OwnerReferences: controller("Deployment", "deploy")}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "other", UID: "other-rs", Labels: labels}},
+		}},
+		"/apis/batch/v1/namespaces/test/jobs": metav1.List{},
+		"/api/v1/namespaces/test/pods": corev1.PodList{Items: []corev1.Pod{
+			{ObjectMeta: metav1.ObjectMeta{Name: "web-1-a", Namespace: "test", UID: "pod", Labels: labels, OwnerReferences: controller("ReplicaSet", "rs")}, Status: crashing},
+			{ObjectMeta: metav1.ObjectMeta{Name: "other-a", Namespace: "test", UID: "other-pod", Labels: labels, OwnerReferences: controller("ReplicaSet", "other-rs")}, Status: crashing},
+		}},
+		"/api/v1/namespaces/test/events": corev1.EventList{},
+	}
+	c := testServerClient(t, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		resp, ok := responses[req.URL.Path]
+		if !ok {
+			http.NotFound(w, req)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+
+	info := testInfo(t, `{"apiVersion": "apps/v1", "kind": "Deployment", "metadata": {"name": "web", "namespace": "test"}}`)
+	info.Mapping.Resource.Resource = "deployments"
+	client, err := c.factory.ClientForMapping(info.Mapping)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info.Client = client
+
+	diag, err := c.diagnose(info)
+	if err != nil {
+		t.Fatalf("Client.diagnose() error = %v", err)
+	}
+	if len(diag.Pods) != 1 || diag.Pods[0].Name != "web-1-a" {
+		t.Errorf("Client.diagnose() pods = %+v, want the pod web-1-a", diag.Pods)
+	}
+}
@@ -0,0 +1,124 @@
+package klient
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/resource"
+	"sigs.k8s.io/yaml"
+)
+
+// Diff returns the differences between the objects in the cluster and the
+// objects in the given content as they would be applied, like `kubectl diff`
+func (c *Client) Diff(content []byte) (string, error) {
+	r := c.ResultForContent(content, nil)
+	return c.DiffResource(r)
+}
+
+// DiffFiles returns the differences between the objects in the cluster and
+// the objects from the given filenames (file, directory or STDIN) or HTTP URLs
+// as they would be applied
+func (c *Client) DiffFiles(filenames ...string) (string, error) {
+	r := c.ResultForFilenameParam(filenames, nil)
+	return c.DiffResource(r)
+}
+
+// DiffResource returns the unified diff between the live objects and the
+// objects of the given resource as they would be applied. The merged objects
+// are the result of a server-side dry-run apply, so they include the defaults
+// and mutations of the server. The managed fields are not compared. Create the
+// resources with `ResultForFilenameParam` or `ResultForContent`
+func (c *Client) DiffResource(r *resource.Result) (string, error) {
+	if err := r.Err(); err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	applyFn := c.applier()
+	err := c.visit(r, func(info *resource.Info, err error) error {
+		if err != nil {
+			return failedTo("diff", info, err)
+		}
+		live, err := resource.NewHelper(info.Client, info.Mapping).Get(info.Namespace, info.Name, false)
+		if err != nil {
+			if !errors.IsNotFound(err) {
+				return failedTo("retrieve current configuration", info, err)
+			}
+			live = nil
+		}
+
+		dryRun(info)
+		if err := applyFn(info, nil); err != nil {
+			return err
+		}
+		diff, err := diffObjects(diffName(info), live, info.Object)
+		if err != nil {
+			return failedTo("diff", info, err)
+		}
+		b.WriteString(diff)
+		return nil
+	}, c.enforcePolicy)
+
+	return b.String(), err
+}
+
+// diffName returns the name of the object in the diff, in the format of
+// kubectl: `group.version.kind.namespace.name`
+func diffName(info *resource.Info) string {
+	gvk := info.Mapping.GroupVersionKind
+	parts := []string{gvk.Group, gvk.Version, gvk.Kind, info.Namespace, info.Name}
+	var name []string
+	for _, p := range parts {
+		if p != "" {
+			name = append(name, p)
+		}
+	}
+	return strings.Join(name, ".")
+}
+
+// diffObjects returns the unified diff of the YAML of the objects, empty if
+// they are equal. A nil object is an empty file, i.e. it does not exists
+func diffObjects(name string, live, merged runtime.Object) (string, error) {
+	from, err := diffYAML(live)
+	if err != nil {
+		return "", err
+	}
+	to, err := diffYAML(merged)
+	if err != nil {
+		return "", err
+	}
+	if from == to {
+		return "", nil
+	}
+
+	fromFile, toFile := "live/"+name, "merged/"+name
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(from),
+		B:        difflib.SplitLines(to),
+		FromFile: fromFile,
+		ToFile:   toFile,
+		Context:  3,
+	})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("diff -u -N %s %s\n%s", fromFile, toFile, diff), nil
+}
+
+// diffYAML returns the object in YAML without the managed fields
+func diffYAML(obj runtime.Object) (string, error) {
+	if obj == nil {
+		return "", nil
+	}
+	content := runtime.DeepCopyJSON(unstructuredContent(obj))
+	unstructured.RemoveNestedField(content, "metadata", "managedFields")
+	data, err := yaml.Marshal(content)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
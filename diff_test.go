@@ -0,0 +1,44 @@
+package klient
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestDiffObjects(t *testing.T) {
+	live := testInfo(t, `{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "web", "namespace": "apps", "managedFields": [{"manager": "kubectl"}]}, "data": {"color": "blue"}}`).Object
+	merged := testInfo(t, `{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "web", "namespace": "apps", "managedFields": [{"manager": "klient"}]}, "data": {"color": "green"}}`).Object
+	unchanged := testInfo(t, `{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "web", "namespace": "apps"}, "data": {"color": "blue"}}`).Object
+
+	tests := []struct {
+		name   string
+		live   runtime.Object
+		merged runtime.Object
+		want   []string
+	}{
+		{"changed", live, merged, []string{"diff -u -N live/v1.ConfigMap.apps.web merged/v1.ConfigMap.apps.web", "-  color: blue", "+  color: green"}},
+		{"new object", nil, merged, []string{"--- live/v1.ConfigMap.apps.web", "+kind: ConfigMap"}},
+		{"managed fields only", live, unchanged, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := diffObjects("v1.ConfigMap.apps.web", tt.live, tt.merged)
+			if err != nil {
+				t.Fatalf("diffObjects() error = %v", err)
+			}
+			if len(tt.want) == 0 && got != "" {
+				t.Errorf("diffObjects() = %q, want no differences", got)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("diffObjects() = %q, want to contain %q", got, want)
+				}
+			}
+			if strings.Contains(got, "managedFields") {
+				t.Errorf("diffObjects() = %q, want without the managed fields", got)
+			}
+		})
+	}
+}
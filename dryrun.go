@@ -0,0 +1,41 @@
+package klient
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/client-go/rest"
+)
+
+// dryRunClient is a REST client that sends the requests that modify objects
+// as server-side dry-run, so they are validated and mutated by the server
+// but not persisted
+type dryRunClient struct {
+	resource.RESTClient
+}
+
+var _ resource.RESTClient = dryRunClient{}
+
+func (c dryRunClient) Post() *rest.Request {
+	return c.RESTClient.Post().Param("dryRun", metav1.DryRunAll)
+}
+
+func (c dryRunClient) Patch(pt types.PatchType) *rest.Request {
+	return c.RESTClient.Patch(pt).Param("dryRun", metav1.DryRunAll)
+}
+
+func (c dryRunClient) Put() *rest.Request {
+	return c.RESTClient.Put().Param("dryRun", metav1.DryRunAll)
+}
+
+func (c dryRunClient) Delete() *rest.Request {
+	return c.RESTClient.Delete().Param("dryRun", metav1.DryRunAll)
+}
+
+// dryRun makes the requests to modify the object of the resource server-side
+// dry-run
+func dryRun(info *resource.Info) {
+	if _, ok := info.Client.(dryRunClient); !ok && info.Client != nil {
+		info.Client = dryRunClient{info.Client}
+	}
+}
@@ -0,0 +1,54 @@
+package klient
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestDryRun(t *testing.T) {
+	var got []string
+	c := testServerClient(t, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		got = append(got, req.Method+" "+req.URL.Query().Get("dryRun"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "web", "namespace": "test"}}`))
+	}))
+
+	info := testInfo(t, `{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "web", "namespace": "test"}}`)
+	info.Mapping.Resource.Resource = "configmaps"
+	client, err := c.factory.ClientForMapping(info.Mapping)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info.Client = client
+	dryRun(info)
+	dryRun(info)
+
+	if err := create(info, nil); err != nil {
+		t.Fatalf("create() error = %v", err)
+	}
+	if err := replace(info, nil); err != nil {
+		t.Fatalf("replace() error = %v", err)
+	}
+	if err := delete(info, nil); err != nil {
+		t.Fatalf("delete() error = %v", err)
+	}
+	if _, err := info.Client.Get().Namespace("test").Resource("configmaps").Name("web").Do().Get(); err != nil {
+		t.Fatalf("get error = %v", err)
+	}
+
+	// The requests to read objects are not dry-run
+	methods := map[string]bool{}
+	for _, r := range got {
+		parts := strings.SplitN(r, " ", 2)
+		methods[parts[0]] = true
+		if want := parts[0] != http.MethodGet; (parts[1] == "All") != want {
+			t.Errorf("dryRun() request %q, want dry-run %v", r, want)
+		}
+	}
+	for _, m := range []string{http.MethodPost, http.MethodPut, http.MethodDelete, http.MethodGet} {
+		if !methods[m] {
+			t.Errorf("dryRun() requests = %q, want a %s request", got, m)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package klient
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// Exec executes the command in the container of the pod, like `kubectl exec`.
+// The stdin, if not nil, is sent to the command and the command output is
+// copied to stdout and stderr. If tty is true, stderr is merged into stdout. If
+// the container is empty and the pod has a single container, it's used. When
+// the context is done Exec returns, the remote command may continue running
+func (c *Client) Exec(ctx context.Context, namespace, pod, container string, cmd []string, stdin io.Reader, stdout, stderr io.Writer, tty bool) error {
+	if namespace == "" {
+		namespace = c.namespace
+	}
+	config, err := c.factory.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+
+	req := c.Clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(pod).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: container,
+			Command:   cmd,
+			Stdin:     stdin != nil,
+			Stdout:    stdout != nil,
+			Stderr:    stderr != nil && !tty,
+			TTY:       tty,
+		}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(config, "POST", req.URL())
+	if err != nil {
+		return fmt.Errorf("cannot exec into the pod %q. %s", pod, err)
+	}
+
+	options := remotecommand.StreamOptions{
+		Stdin:  stdin,
+		Stdout: stdout,
+		Tty:    tty,
+	}
+	if !tty {
+		options.Stderr = stderr
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- executor.Stream(options)
+	}()
+
+	select {
+	case err := <-errCh:
+		if err != nil {
+			return fmt.Errorf("cannot exec %q in the pod %q. %s", cmd, pod, err)
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,133 @@
+package klient
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/apimachinery/pkg/util/httpstream/spdy"
+)
+
+// testServerClient returns a client for a fake API server with the given handler
+func testServerClient(t *testing.T, handler http.Handler) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	dir, err := ioutil.TempDir("", "klient")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	kubeconfig := filepath.Join(dir, "config")
+	config := fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+- name: test
+  cluster:
+    server: %s
+contexts:
+- name: test
+  context:
+    cluster: test
+    namespace: test
+current-context: test
+`, server.URL)
+	if err := ioutil.WriteFile(kubeconfig, []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := NewE("test", kubeconfig)
+	if err != nil {
+		t.Fatalf("failed to create the client for the test server. %s", err)
+	}
+	return c
+}
+
+// execHandler is a fake exec endpoint that echoes the stdin to stdout and the
+// command to stderr
+func execHandler(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if !strings.HasSuffix(req.URL.Path, "/namespaces/test/pods/web/exec") {
+			http.NotFound(w, req)
+			return
+		}
+		if _, err := httpstream.Handshake(req, w, []string{"v4.channel.k8s.io"}); err != nil {
+			t.Errorf("failed the exec handshake. %s", err)
+			return
+		}
+
+		query := req.URL.Query()
+		expected := 1 // error stream
+		for _, s := range []string{"stdin", "stdout", "stderr"} {
+			if query.Get(s) == "true" {
+				expected++
+			}
+		}
+		streamCh := make(chan httpstream.Stream, expected)
+		conn := spdy.NewResponseUpgrader().UpgradeResponse(w, req, func(s httpstream.Stream, replySent <-chan struct{}) error {
+			streamCh <- s
+			return nil
+		})
+		if conn == nil {
+			return
+		}
+		defer conn.Close()
+
+		streams := map[string]httpstream.Stream{}
+		for i := 0; i < expected; i++ {
+			s := <-streamCh
+			streams[s.Headers().Get(corev1.StreamType)] = s
+		}
+		if stdin, ok := streams[corev1.StreamTypeStdin]; ok {
+			io.Copy(streams[corev1.StreamTypeStdout], stdin)
+		}
+		if stderr, ok := streams[corev1.StreamTypeStderr]; ok {
+			fmt.Fprint(stderr, strings.Join(query["command"], " "))
+		}
+		for _, s := range streams {
+			s.Close()
+		}
+	}
+}
+
+func TestClient_Exec(t *testing.T) {
+	c := testServerClient(t, execHandler(t))
+
+	tests := []struct {
+		name       string
+		pod        string
+		stdin      io.Reader
+		wantStdout string
+		wantStderr string
+		wantErr    bool
+	}{
+		{"stdin", "web", strings.NewReader("hello"), "hello", "cat -", false},
+		{"no stdin", "web", nil, "", "cat -", false},
+		{"pod not found", "db", nil, "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			err := c.Exec(context.Background(), "", tt.pod, "", []string{"cat", "-"}, tt.stdin, &stdout, &stderr, false)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Client.Exec() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := stdout.String(); got != tt.wantStdout {
+				t.Errorf("Client.Exec() stdout = %q, want %q", got, tt.wantStdout)
+			}
+			if got := stderr.String(); got != tt.wantStderr {
+				t.Errorf("Client.Exec() stderr = %q, want %q", got, tt.wantStderr)
+			}
+		})
+	}
+}
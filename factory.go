@@ -1,6 +1,7 @@
 package klient
 
 import (
+	"fmt"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -159,6 +160,25 @@ func (f *factory) ToRESTMapper() (meta.RESTMapper, error) {
 	return expander, nil
 }
 
+// refreshDiscovery invalidates the cached discovery and fetches it again, so
+// the REST mappers created after it know about new or removed resources, i.e.
+// after install or uninstall CRDs
+func (f *factory) refreshDiscovery() error {
+	discoveryClient, err := f.ToDiscoveryClient()
+	if err != nil {
+		return err
+	}
+	discoveryClient.Invalidate()
+
+	// The disk cache is rewritten with the new discovery. The groups failing to
+	// be discovered are ignored, like the REST mapper does
+	_, _, err = discoveryClient.ServerGroupsAndResources()
+	if err != nil && !discovery.IsGroupDiscoveryFailedError(err) {
+		return fmt.Errorf("cannot refresh the discovery cache. %s", err)
+	}
+	return nil
+}
+
 // KubernetesClientSet creates a kubernetes clientset from the configuration
 // It's required to implement the Factory interface
 func (f *factory) KubernetesClientSet() (*kubernetes.Clientset, error) {
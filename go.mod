@@ -4,7 +4,11 @@ go 1.14
 
 require (
 	github.com/evanphx/json-patch v4.5.0+incompatible // indirect
+	github.com/googleapis/gnostic v0.0.0-20170729233727-0c5108395e2d
 	github.com/jonboulle/clockwork v0.1.0
+	github.com/pmezard/go-difflib v1.0.0
+	github.com/spf13/cobra v0.0.5
+	gopkg.in/yaml.v3 v3.0.1
 	k8s.io/api v0.17.3
 	k8s.io/apiextensions-apiserver v0.17.3
 	k8s.io/apimachinery v0.17.3
@@ -12,4 +16,5 @@ require (
 	k8s.io/client-go v0.17.3
 	k8s.io/kube-openapi v0.0.0-20191107075043-30be4d16710a
 	k8s.io/kubectl v0.17.3
+	sigs.k8s.io/yaml v1.1.0
 )
@@ -0,0 +1,93 @@
+//go:build ignore
+// +build ignore
+
+// bundle-openapi generates the Go file with a compressed OpenAPI v2 document
+// to validate the manifests of the given Kubernetes version without a cluster.
+// Only the definitions are kept, without descriptions, to reduce the size.
+//
+// Usage:
+//
+//	go run hack/bundle-openapi.go -version v1.16 -in swagger.json -out openapi_v1_16.go
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"strings"
+)
+
+const tmpl = `// Code generated by hack/bundle-openapi.go. DO NOT EDIT.
+
+package klient
+
+func init() {
+	bundledOpenAPISchemas[%q] = %q
+}
+`
+
+func main() {
+	version := flag.String("version", "", "Kubernetes version of the OpenAPI document, i.e. v1.16")
+	in := flag.String("in", "swagger.json", "OpenAPI v2 document to bundle")
+	out := flag.String("out", "", "Go file to generate")
+	flag.Parse()
+
+	if *version == "" || *out == "" {
+		log.Fatal("the version and the output file are required")
+	}
+
+	data, err := ioutil.ReadFile(*in)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		log.Fatal(err)
+	}
+
+	definitions, _ := doc["definitions"].(map[string]interface{})
+	for _, def := range definitions {
+		removeDescriptions(def)
+	}
+	bundle := map[string]interface{}{
+		"swagger":     doc["swagger"],
+		"info":        doc["info"],
+		"paths":       map[string]interface{}{},
+		"definitions": definitions,
+	}
+	data, err = json.Marshal(bundle)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	gz, _ := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	gz.Write(data)
+	gz.Close()
+
+	code := fmt.Sprintf(tmpl, strings.TrimPrefix(*version, "v"), base64.StdEncoding.EncodeToString(buf.Bytes()))
+	if err := ioutil.WriteFile(*out, []byte(code), 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func removeDescriptions(v interface{}) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		if _, ok := t["description"].(string); ok {
+			delete(t, "description")
+		}
+		for _, e := range t {
+			removeDescriptions(e)
+		}
+	case []interface{}:
+		for _, e := range t {
+			removeDescriptions(e)
+		}
+	}
+}
@@ -0,0 +1,278 @@
+package klient
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/kubectl/pkg/util/storage"
+)
+
+// DefaultHealthCheckTimeout is the time to wait for every health check
+const DefaultHealthCheckTimeout = 10 * time.Second
+
+// HealthCheck is the result of a cluster health check
+type HealthCheck struct {
+	// Name of the check, i.e. `readyz`, `nodes` or `storage-classes`
+	Name    string
+	Healthy bool
+	// Skipped is true if the check is not available in the cluster, i.e. the
+	// component statuses in recent Kubernetes versions. It's healthy
+	Skipped bool
+	Message string
+	// Details are the individual checks or the objects not healthy
+	Details []string
+}
+
+// HealthReport is the result of every cluster health check
+type HealthReport struct {
+	Checks []HealthCheck
+}
+
+// Healthy returns true if every check is healthy
+func (r *HealthReport) Healthy() bool {
+	return len(r.Failed()) == 0
+}
+
+// Failed returns the names of the checks not healthy
+func (r *HealthReport) Failed() []string {
+	failed := []string{}
+	for _, check := range r.Checks {
+		if !check.Healthy {
+			failed = append(failed, check.Name)
+		}
+	}
+	return failed
+}
+
+func (r *HealthReport) String() string {
+	var b strings.Builder
+	for _, check := range r.Checks {
+		status := "ok"
+		switch {
+		case check.Skipped:
+			status = "skipped"
+		case !check.Healthy:
+			status = "failed"
+		}
+		fmt.Fprintf(&b, "[%s] %s: %s\n", status, check.Name, check.Message)
+		for _, d := range check.Details {
+			fmt.Fprintf(&b, "  %s\n", d)
+		}
+	}
+	return b.String()
+}
+
+// err returns the errors of the checks not healthy
+func (r *HealthReport) err() error {
+	errs := []error{}
+	for _, check := range r.Checks {
+		if !check.Healthy {
+			errs = append(errs, fmt.Errorf("health check %q failed. %s", check.Name, check.Message))
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// Health checks the health of the cluster with DefaultHealthCheckTimeout for
+// every check. See HealthWithTimeout
+func (c *Client) Health() (*HealthReport, error) {
+	return c.HealthWithTimeout(DefaultHealthCheckTimeout)
+}
+
+// HealthWithTimeout checks the API server readiness and liveness, the nodes
+// readiness, the system pods, the component statuses and the default storage
+// class. Every check fails if it takes longer than the timeout. The returned
+// error aggregates the failed checks, the report has the details
+func (c *Client) HealthWithTimeout(timeout time.Duration) (*HealthReport, error) {
+	config, err := c.factory.ToRESTConfig()
+	if err != nil {
+		return nil, err
+	}
+	config.Timeout = timeout
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &HealthReport{
+		Checks: []HealthCheck{
+			apiServerCheck(clientset, "readyz"),
+			apiServerCheck(clientset, "livez"),
+			nodesCheck(clientset),
+			systemPodsCheck(clientset),
+			componentStatusesCheck(clientset),
+			storageClassesCheck(clientset),
+		},
+	}
+	return report, report.err()
+}
+
+// apiServerCheck checks the API server endpoint `/readyz` or `/livez`. The
+// servers older than 1.16 are checked with `/healthz`
+func apiServerCheck(clientset kubernetes.Interface, endpoint string) HealthCheck {
+	check := HealthCheck{Name: endpoint}
+	get := func(path string) ([]byte, error) {
+		return clientset.Discovery().RESTClient().Get().AbsPath(path).Param("verbose", "").DoRaw()
+	}
+	body, err := get("/" + endpoint)
+	if errors.IsNotFound(err) {
+		body, err = get("/healthz")
+	}
+
+	// The failed individual checks are in the body, even if it fails
+	for _, line := range strings.Split(strings.TrimSpace(string(body)), "\n") {
+		if strings.HasPrefix(line, "[+]") || strings.HasPrefix(line, "[-]") {
+			check.Details = append(check.Details, line)
+		}
+	}
+	if err != nil {
+		check.Message = err.Error()
+		if len(check.Details) != 0 {
+			check.Message = fmt.Sprintf("the API server is not %s", strings.TrimSuffix(endpoint, "z"))
+		}
+		return check
+	}
+	check.Healthy = true
+	check.Message = fmt.Sprintf("the API server is %s", strings.TrimSuffix(endpoint, "z"))
+	return check
+}
+
+// nodesCheck checks every node is ready
+func nodesCheck(clientset kubernetes.Interface) HealthCheck {
+	check := HealthCheck{Name: "nodes"}
+	list, err := clientset.CoreV1().Nodes().List(metav1.ListOptions{})
+	if err != nil {
+		check.Message = fmt.Sprintf("cannot list the nodes. %s", err)
+		return check
+	}
+	ready := 0
+	for i := range list.Items {
+		node := newNode(&list.Items[i])
+		if node.Ready {
+			ready++
+			continue
+		}
+		reason := "the Ready condition is not found"
+		for _, c := range node.Conditions {
+			if c.Type == v1.NodeReady {
+				reason = fmt.Sprintf("Ready is %s, %s", c.Status, c.Message)
+			}
+		}
+		check.Details = append(check.Details, fmt.Sprintf("node %q is not ready: %s", node.Name, reason))
+	}
+	check.Healthy = ready == len(list.Items) && ready != 0
+	check.Message = fmt.Sprintf("%d/%d nodes ready", ready, len(list.Items))
+	return check
+}
+
+// systemPodsCheck checks the pods in the kube-system namespace are running and
+// ready, or completed
+func systemPodsCheck(clientset kubernetes.Interface) HealthCheck {
+	check := HealthCheck{Name: "system-pods"}
+	list, err := clientset.CoreV1().Pods(metav1.NamespaceSystem).List(metav1.ListOptions{})
+	if err != nil {
+		check.Message = fmt.Sprintf("cannot list the pods in %s. %s", metav1.NamespaceSystem, err)
+		return check
+	}
+	healthy := 0
+	for _, pod := range list.Items {
+		if reason := podProblem(&pod); reason != "" {
+			check.Details = append(check.Details, fmt.Sprintf("pod %q %s", pod.Name, reason))
+			continue
+		}
+		healthy++
+	}
+	check.Healthy = healthy == len(list.Items)
+	check.Message = fmt.Sprintf("%d/%d pods in %s healthy", healthy, len(list.Items), metav1.NamespaceSystem)
+	return check
+}
+
+// podProblem returns why the pod is not running and ready, empty if it is or
+// if it has completed
+func podProblem(pod *v1.Pod) string {
+	switch pod.Status.Phase {
+	case v1.PodSucceeded:
+		return ""
+	case v1.PodRunning:
+	default:
+		return fmt.Sprintf("is %s %s", pod.Status.Phase, pod.Status.Reason)
+	}
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.Ready {
+			continue
+		}
+		if cs.State.Waiting != nil {
+			return fmt.Sprintf("has the container %q waiting, %s", cs.Name, cs.State.Waiting.Reason)
+		}
+		return fmt.Sprintf("has the container %q not ready", cs.Name)
+	}
+	return ""
+}
+
+// componentStatusesCheck checks the health of the control plane components. It's
+// skipped if the component statuses are not available
+func componentStatusesCheck(clientset kubernetes.Interface) HealthCheck {
+	check := HealthCheck{Name: "component-statuses"}
+	list, err := clientset.CoreV1().ComponentStatuses().List(metav1.ListOptions{})
+	if errors.IsNotFound(err) || errors.IsForbidden(err) || (err == nil && len(list.Items) == 0) {
+		check.Healthy, check.Skipped = true, true
+		check.Message = "the component statuses are not available"
+		return check
+	}
+	if err != nil {
+		check.Message = fmt.Sprintf("cannot list the component statuses. %s", err)
+		return check
+	}
+	healthy := 0
+	for _, cs := range list.Items {
+		ok, message := false, "the Healthy condition is not found"
+		for _, c := range cs.Conditions {
+			if c.Type == v1.ComponentHealthy {
+				ok, message = c.Status == v1.ConditionTrue, c.Message
+				if c.Error != "" {
+					message = c.Error
+				}
+			}
+		}
+		if ok {
+			healthy++
+			continue
+		}
+		check.Details = append(check.Details, fmt.Sprintf("component %q is not healthy: %s", cs.Name, message))
+	}
+	check.Healthy = healthy == len(list.Items)
+	check.Message = fmt.Sprintf("%d/%d components healthy", healthy, len(list.Items))
+	return check
+}
+
+// storageClassesCheck checks there is a default storage class
+func storageClassesCheck(clientset kubernetes.Interface) HealthCheck {
+	check := HealthCheck{Name: "storage-classes"}
+	list, err := clientset.StorageV1().StorageClasses().List(metav1.ListOptions{})
+	if err != nil {
+		check.Message = fmt.Sprintf("cannot list the storage classes. %s", err)
+		return check
+	}
+	defaults := []string{}
+	for _, sc := range list.Items {
+		if storage.IsDefaultAnnotationText(sc.ObjectMeta) == "Yes" {
+			defaults = append(defaults, sc.Name)
+		}
+	}
+	switch len(defaults) {
+	case 0:
+		check.Message = fmt.Sprintf("there is no default storage class out of %d", len(list.Items))
+	case 1:
+		check.Healthy = true
+		check.Message = fmt.Sprintf("the default storage class is %q", defaults[0])
+	default:
+		check.Message = fmt.Sprintf("there are %d default storage classes: %s", len(defaults), strings.Join(defaults, ", "))
+	}
+	return check
+}
@@ -0,0 +1,61 @@
+package klient
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestClient_Health(t *testing.T) {
+	responses := map[string]string{
+		"/livez": "[+]ping ok\n[+]etcd ok\nlivez check passed\n",
+		"/api/v1/nodes": `{"kind": "NodeList", "apiVersion": "v1", "items": [
+			{"metadata": {"name": "master"}, "status": {"conditions": [{"type": "Ready", "status": "True"}]}},
+			{"metadata": {"name": "worker"}, "status": {"conditions": [{"type": "Ready", "status": "False", "message": "kubelet stopped posting node status"}]}}]}`,
+		"/api/v1/namespaces/kube-system/pods": `{"kind": "PodList", "apiVersion": "v1", "items": [
+			{"metadata": {"name": "coredns"}, "status": {"phase": "Running", "containerStatuses": [{"name": "coredns", "ready": true}]}},
+			{"metadata": {"name": "kube-proxy"}, "status": {"phase": "Running", "containerStatuses": [{"name": "kube-proxy", "ready": false, "state": {"waiting": {"reason": "CrashLoopBackOff"}}}]}},
+			{"metadata": {"name": "install-cni"}, "status": {"phase": "Succeeded"}}]}`,
+		"/apis/storage.k8s.io/v1/storageclasses": `{"kind": "StorageClassList", "apiVersion": "storage.k8s.io/v1", "items": [
+			{"metadata": {"name": "standard", "annotations": {"storageclass.kubernetes.io/is-default-class": "true"}}, "provisioner": "rancher.io/local-path"}]}`,
+	}
+	c := testServerClient(t, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/readyz" {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte("[+]ping ok\n[-]etcd failed: reason withheld\nreadyz check failed\n"))
+			return
+		}
+		resp, ok := responses[req.URL.Path]
+		if !ok {
+			http.NotFound(w, req)
+			return
+		}
+		if strings.HasPrefix(resp, "{") {
+			w.Header().Set("Content-Type", "application/json")
+		}
+		w.Write([]byte(resp))
+	}))
+
+	report, err := c.Health()
+	if err == nil {
+		t.Fatalf("Client.Health() error = nil, want the failed checks")
+	}
+	if want := []string{"readyz", "nodes", "system-pods"}; !reflect.DeepEqual(report.Failed(), want) {
+		t.Errorf("Client.Health() failed = %v, want %v\n%s", report.Failed(), want, report)
+	}
+
+	want := map[string]HealthCheck{
+		"readyz":             {Name: "readyz", Message: "the API server is not ready", Details: []string{"[+]ping ok", "[-]etcd failed: reason withheld"}},
+		"livez":              {Name: "livez", Healthy: true, Message: "the API server is live", Details: []string{"[+]ping ok", "[+]etcd ok"}},
+		"nodes":              {Name: "nodes", Message: "1/2 nodes ready", Details: []string{`node "worker" is not ready: Ready is False, kubelet stopped posting node status`}},
+		"system-pods":        {Name: "system-pods", Message: "2/3 pods in kube-system healthy", Details: []string{`pod "kube-proxy" has the container "kube-proxy" waiting, CrashLoopBackOff`}},
+		"component-statuses": {Name: "component-statuses", Healthy: true, Skipped: true, Message: "the component statuses are not available"},
+		"storage-classes":    {Name: "storage-classes", Healthy: true, Message: `the default storage class is "standard"`},
+	}
+	for _, got := range report.Checks {
+		if !reflect.DeepEqual(got, want[got.Name]) {
+			t.Errorf("Client.Health() check %s = %#v, want %#v", got.Name, got, want[got.Name])
+		}
+	}
+}
@@ -1,8 +1,14 @@
 package klient
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
 )
 
 // CreateNamespace creates a namespace with the given name
@@ -29,26 +35,150 @@ func (c *Client) DeleteNamespace(namespace string) error {
 	return c.Clientset.CoreV1().Namespaces().Delete(namespace, &metav1.DeleteOptions{})
 }
 
-// NodesReady returns the number of nodes ready
+// EnsureNamespace creates the namespace with the given labels and annotations
+// or, if it exists, adds or updates them. Other labels and annotations of the
+// namespace are kept
+func (c *Client) EnsureNamespace(namespace string, labels, annotations map[string]string) error {
+	namespaces := c.Clientset.CoreV1().Namespaces()
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		ns, err := namespaces.Get(namespace, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			ns = &v1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        namespace,
+					Labels:      map[string]string{"name": namespace},
+					Annotations: annotations,
+				},
+			}
+			for k, v := range labels {
+				ns.Labels[k] = v
+			}
+			_, err = namespaces.Create(ns)
+			if errors.IsAlreadyExists(err) {
+				// Created by someone else in the meantime, retry to update it
+				return errors.NewConflict(v1.Resource("namespaces"), namespace, err)
+			}
+			return err
+		}
+		if err != nil {
+			return err
+		}
+
+		updatedLabels, changedLabels := mergeStringMap(ns.Labels, labels)
+		updatedAnnotations, changedAnnotations := mergeStringMap(ns.Annotations, annotations)
+		if !changedLabels && !changedAnnotations {
+			return nil
+		}
+		ns.Labels, ns.Annotations = updatedLabels, updatedAnnotations
+		_, err = namespaces.Update(ns)
+		return err
+	})
+}
+
+// mergeStringMap adds or updates the values into the map, returns true if the
+// map has changed
+func mergeStringMap(m, values map[string]string) (map[string]string, bool) {
+	changed := false
+	for k, v := range values {
+		if current, ok := m[k]; ok && current == v {
+			continue
+		}
+		if m == nil {
+			m = map[string]string{}
+		}
+		m[k] = v
+		changed = true
+	}
+	return m, changed
+}
+
+// NamespaceTerminationError is the error of a namespace that is not deleted
+// in time, with the reasons it's still terminating
+type NamespaceTerminationError struct {
+	Namespace string
+	// Finalizers are the namespace finalizers not removed yet, i.e. `kubernetes`
+	Finalizers []string
+	// Conditions are the messages of the namespace conditions about the
+	// remaining resources, their finalizers or the failures deleting them
+	Conditions []string
+}
+
+func (e *NamespaceTerminationError) Error() string {
+	msg := fmt.Sprintf("the namespace %q is still terminating", e.Namespace)
+	if len(e.Finalizers) != 0 {
+		msg += fmt.Sprintf(". Finalizers: %s", strings.Join(e.Finalizers, ", "))
+	}
+	if len(e.Conditions) != 0 {
+		msg += fmt.Sprintf(". %s", strings.Join(e.Conditions, ". "))
+	}
+	return msg
+}
+
+// DeleteNamespaceAndWait deletes the namespace with the given name and waits
+// until it's finalized. If the timeout is reached, the returned error is a
+// *NamespaceTerminationError with the finalizers and resources remaining. It's
+// not an error if the namespace does not exists
+func (c *Client) DeleteNamespaceAndWait(namespace string, timeout time.Duration) error {
+	namespaces := c.Clientset.CoreV1().Namespaces()
+	if err := namespaces.Delete(namespace, &metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		ns, err := namespaces.Get(namespace, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if time.Now().Add(readinessInterval).After(deadline) {
+			return namespaceTerminationError(ns)
+		}
+		time.Sleep(readinessInterval)
+	}
+}
+
+// namespaceTerminationError returns the reasons the namespace is still
+// terminating
+func namespaceTerminationError(ns *v1.Namespace) *NamespaceTerminationError {
+	e := &NamespaceTerminationError{Namespace: ns.Name}
+	for _, f := range ns.Spec.Finalizers {
+		e.Finalizers = append(e.Finalizers, string(f))
+	}
+	for _, cond := range ns.Status.Conditions {
+		if cond.Status == v1.ConditionTrue && cond.Message != "" {
+			e.Conditions = append(e.Conditions, cond.Message)
+		}
+	}
+	return e
+}
+
+// ListNamespaces returns the namespaces matching the label selector, i.e.
+// `env=prod,team!=ops`. All the namespaces are returned if it's empty
+func (c *Client) ListNamespaces(selector string) ([]v1.Namespace, error) {
+	list, err := c.Clientset.CoreV1().Namespaces().List(metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// NodesReady returns the number of nodes ready. Use Nodes to get the state of
+// every node
 func (c *Client) NodesReady() (ready int, total int, err error) {
-	nodes, err := c.Clientset.CoreV1().Nodes().List(metav1.ListOptions{})
+	nodes, err := c.Nodes("")
 	if err != nil {
 		return 0, 0, err
 	}
-	total = len(nodes.Items)
-	if total == 0 {
-		return 0, 0, nil
-	}
-	for _, n := range nodes.Items {
-		for _, c := range n.Status.Conditions {
-			if c.Type == "Ready" && c.Status == "True" {
-				ready++
-				break
-			}
+	for _, n := range nodes {
+		if n.Ready {
+			ready++
 		}
 	}
 
-	return ready, len(nodes.Items), nil
+	return ready, len(nodes), nil
 }
 
 // Version returns the cluster version. It can be used to verify if the cluster
@@ -1,11 +1,17 @@
 package klient
 
 import (
+	"encoding/json"
+	stderrors "errors"
 	"log"
+	"net/http"
 	"os"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -70,6 +76,165 @@ func TestClient_CreateAndDeleteNamespace(t *testing.T) {
 	}
 }
 
+// namespacesHandler is a fake namespaces endpoint with the given namespaces.
+// The deleted namespaces are removed after the given number of requests
+func namespacesHandler(t *testing.T, namespaces map[string]*v1.Namespace, terminationRequests int) http.HandlerFunc {
+	terminating, deleted := map[string]int{}, map[string]bool{}
+	return func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		name := strings.TrimPrefix(strings.TrimPrefix(req.URL.Path, "/api/v1/namespaces"), "/")
+		notFound := func() {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(errors.NewNotFound(v1.Resource("namespaces"), name).Status())
+		}
+
+		switch {
+		case req.Method == http.MethodGet && name == "":
+			list := v1.NamespaceList{}
+			for _, ns := range namespaces {
+				if sel := req.URL.Query().Get("labelSelector"); sel == "" || ns.Labels[strings.Split(sel, "=")[0]] == strings.Split(sel, "=")[1] {
+					list.Items = append(list.Items, *ns)
+				}
+			}
+			json.NewEncoder(w).Encode(list)
+		case req.Method == http.MethodPost:
+			ns := &v1.Namespace{}
+			json.NewDecoder(req.Body).Decode(ns)
+			namespaces[ns.Name] = ns
+			json.NewEncoder(w).Encode(ns)
+		case req.Method == http.MethodPut:
+			ns := &v1.Namespace{}
+			json.NewDecoder(req.Body).Decode(ns)
+			namespaces[name] = ns
+			json.NewEncoder(w).Encode(ns)
+		case req.Method == http.MethodDelete:
+			ns, ok := namespaces[name]
+			if !ok || deleted[name] {
+				notFound()
+				return
+			}
+			ns.Status.Phase = v1.NamespaceTerminating
+			terminating[name] = terminationRequests
+			json.NewEncoder(w).Encode(ns)
+		case req.Method == http.MethodGet:
+			ns, ok := namespaces[name]
+			if n, deleting := terminating[name]; deleting && n == 0 {
+				deleted[name] = true
+			} else if deleting {
+				terminating[name]--
+			}
+			if !ok || deleted[name] {
+				notFound()
+				return
+			}
+			json.NewEncoder(w).Encode(ns)
+		default:
+			t.Errorf("unexpected request %s %s", req.Method, req.URL.Path)
+		}
+	}
+}
+
+func TestClient_EnsureNamespace(t *testing.T) {
+	namespaces := map[string]*v1.Namespace{
+		"apps": {ObjectMeta: metav1.ObjectMeta{Name: "apps", Labels: map[string]string{"name": "apps", "team": "web"}}},
+	}
+	c := testServerClient(t, namespacesHandler(t, namespaces, 0))
+
+	tests := []struct {
+		name            string
+		namespace       string
+		labels          map[string]string
+		annotations     map[string]string
+		wantLabels      map[string]string
+		wantAnnotations map[string]string
+	}{
+		{"create", "db", map[string]string{"env": "prod"}, map[string]string{"owner": "dba"}, map[string]string{"name": "db", "env": "prod"}, map[string]string{"owner": "dba"}},
+		{"update", "apps", map[string]string{"team": "api", "env": "prod"}, map[string]string{"owner": "sre"}, map[string]string{"name": "apps", "team": "api", "env": "prod"}, map[string]string{"owner": "sre"}},
+		{"unchanged", "apps", map[string]string{"env": "prod"}, nil, map[string]string{"name": "apps", "team": "api", "env": "prod"}, map[string]string{"owner": "sre"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := c.EnsureNamespace(tt.namespace, tt.labels, tt.annotations); err != nil {
+				t.Fatalf("Client.EnsureNamespace() error = %v", err)
+			}
+			ns := namespaces[tt.namespace]
+			if !reflect.DeepEqual(ns.Labels, tt.wantLabels) {
+				t.Errorf("Client.EnsureNamespace() labels = %v, want %v", ns.Labels, tt.wantLabels)
+			}
+			if !reflect.DeepEqual(ns.Annotations, tt.wantAnnotations) {
+				t.Errorf("Client.EnsureNamespace() annotations = %v, want %v", ns.Annotations, tt.wantAnnotations)
+			}
+		})
+	}
+}
+
+func TestClient_DeleteNamespaceAndWait(t *testing.T) {
+	readinessInterval = 10 * time.Millisecond
+	defer func() { readinessInterval = 2 * time.Second }()
+
+	namespaces := map[string]*v1.Namespace{
+		"apps": {ObjectMeta: metav1.ObjectMeta{Name: "apps"}},
+		"stuck": {
+			ObjectMeta: metav1.ObjectMeta{Name: "stuck"},
+			Spec:       v1.NamespaceSpec{Finalizers: []v1.FinalizerName{v1.FinalizerKubernetes}},
+			Status: v1.NamespaceStatus{Conditions: []v1.NamespaceCondition{
+				{Type: v1.NamespaceContentRemaining, Status: v1.ConditionTrue, Message: "Some resources are remaining: crontabs.stable.example.com has 1 resource instances"},
+				{Type: v1.NamespaceDeletionDiscoveryFailure, Status: v1.ConditionFalse, Message: "All resources successfully discovered"},
+			}},
+		},
+	}
+	c := testServerClient(t, namespacesHandler(t, namespaces, 3))
+
+	tests := []struct {
+		name      string
+		namespace string
+		timeout   time.Duration
+		want      *NamespaceTerminationError
+	}{
+		{"deleted", "apps", time.Second, nil},
+		{"not found", "db", time.Second, nil},
+		{"stuck", "stuck", 20 * time.Millisecond, &NamespaceTerminationError{
+			Namespace:  "stuck",
+			Finalizers: []string{"kubernetes"},
+			Conditions: []string{"Some resources are remaining: crontabs.stable.example.com has 1 resource instances"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := c.DeleteNamespaceAndWait(tt.namespace, tt.timeout)
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("Client.DeleteNamespaceAndWait() error = %v, want nil", err)
+				}
+				return
+			}
+			var got *NamespaceTerminationError
+			if !stderrors.As(err, &got) {
+				t.Fatalf("Client.DeleteNamespaceAndWait() error = %v, want a NamespaceTerminationError", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Client.DeleteNamespaceAndWait() error = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_ListNamespaces(t *testing.T) {
+	namespaces := map[string]*v1.Namespace{
+		"apps": {ObjectMeta: metav1.ObjectMeta{Name: "apps", Labels: map[string]string{"env": "prod"}}},
+		"test": {ObjectMeta: metav1.ObjectMeta{Name: "test", Labels: map[string]string{"env": "dev"}}},
+	}
+	c := testServerClient(t, namespacesHandler(t, namespaces, 0))
+
+	got, err := c.ListNamespaces("env=prod")
+	if err != nil {
+		t.Fatalf("Client.ListNamespaces() error = %v", err)
+	}
+	if len(got) != 1 || got[0].Name != "apps" {
+		t.Errorf("Client.ListNamespaces() = %v, want the namespace apps", got)
+	}
+}
+
 func TestClient_Version(t *testing.T) {
 	envContext := os.Getenv(contextEnvVarName)
 	envKubeconfig := os.Getenv(kubeconfigEnvVarName)
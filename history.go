@@ -0,0 +1,362 @@
+package klient
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/resource"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// DefaultReleaseHistory is the number of revisions to keep for every
+	// release if the client does not set ReleaseHistory
+	DefaultReleaseHistory = 10
+
+	// revisionLabel is the label with the revision of the history Secrets
+	revisionLabel = "klient.johandry.com/revision"
+	// manifestsKey is the key of the history data with the compressed manifests
+	manifestsKey = "manifests"
+)
+
+// Revision is a previously applied set of manifests of a release
+type Revision struct {
+	Release   *Release
+	Manifests []byte
+}
+
+// RollbackReport is the summary of the differences between the current
+// release and the revision restored by Rollback
+type RollbackReport struct {
+	Release      string
+	FromRevision int
+	ToRevision   int
+	// Revision is the new revision of the release created by the rollback
+	Revision  int
+	Created   []ObjectReference
+	Changed   []ObjectReference
+	Unchanged []ObjectReference
+	Pruned    []ObjectReference
+}
+
+func (r *RollbackReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "release %q rolled back from revision %d to %d (revision %d)\n", r.Release, r.FromRevision, r.ToRevision, r.Revision)
+	for _, group := range []struct {
+		action  string
+		objects []ObjectReference
+	}{{"created", r.Created}, {"changed", r.Changed}, {"unchanged", r.Unchanged}, {"pruned", r.Pruned}} {
+		for _, obj := range group.objects {
+			fmt.Fprintf(&b, "%s %s\n", obj, group.action)
+		}
+	}
+	return b.String()
+}
+
+// History returns the stored revisions of the release with the given name,
+// sorted from the oldest to the latest
+func (c *Client) History(name string) ([]*Revision, error) {
+	list, err := c.Clientset.CoreV1().Secrets(c.releaseNamespace()).List(metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s,%s=%s,%s", managedByLabel, managedBy, releaseLabel, name, revisionLabel),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	revisions := make([]*Revision, 0, len(list.Items))
+	for i := range list.Items {
+		revision, err := decodeRevision(&list.Items[i])
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, revision)
+	}
+	sort.Slice(revisions, func(i, j int) bool { return revisions[i].Release.Revision < revisions[j].Release.Revision })
+
+	return revisions, nil
+}
+
+// GetRevision returns the given revision of the release with the given name
+func (c *Client) GetRevision(name string, revision int) (*Revision, error) {
+	secret, err := c.Clientset.CoreV1().Secrets(c.releaseNamespace()).Get(revisionName(name, revision), metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, fmt.Errorf("the revision %d of the release %q is not in the history", revision, name)
+		}
+		return nil, err
+	}
+	return decodeRevision(secret)
+}
+
+// Rollback re-applies the manifests of the given revision of the release,
+// deletes the objects that were not in that revision and returns a report of
+// the differences. The rollback is recorded as a new revision. If revision is
+// 0 the release is rolled back to the previous revision. The objects that
+// fail to be pruned are kept in the release. In dry-run, nothing is recorded
+// and the pruning is server-side dry-run
+func (c *Client) Rollback(name string, revision int) (*RollbackReport, error) {
+	current, err := c.GetRelease(name)
+	if err != nil {
+		return nil, err
+	}
+	if revision <= 0 {
+		revision = current.Revision - 1
+	}
+	if revision <= 0 || revision >= current.Revision {
+		return nil, fmt.Errorf("cannot rollback the release %q to revision %d, the current revision is %d", name, revision, current.Revision)
+	}
+
+	target, err := c.GetRevision(name, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &RollbackReport{
+		Release:      name,
+		FromRevision: current.Revision,
+		ToRevision:   revision,
+	}
+	r := c.ResultForContent(target.Manifests, nil)
+	infos, err := r.Infos()
+	if err != nil {
+		return nil, err
+	}
+	for _, info := range infos {
+		changed, found, err := c.changedSinceApplied(info)
+		if err != nil {
+			return nil, err
+		}
+		ref := objectReferences([]*resource.Info{info})[0]
+		switch {
+		case !found:
+			report.Created = append(report.Created, ref)
+		case changed:
+			report.Changed = append(report.Changed, ref)
+		default:
+			report.Unchanged = append(report.Unchanged, ref)
+		}
+	}
+
+	var pruneErr error
+	release, err := c.applyRelease(name, r, func(stale []ObjectReference) error {
+		report.Pruned = stale
+		pruneErr = c.deleteObjects(stale)
+		return pruneErr
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot rollback the release %q to revision %d. %s", name, revision, err)
+	}
+	report.Revision = release.Revision
+	if pruneErr != nil {
+		return report, fmt.Errorf("cannot prune the objects of the release %q. %s", name, pruneErr)
+	}
+
+	return report, nil
+}
+
+// changedSinceApplied returns true if the last applied configuration of the
+// live object, stored by apply in the annotation, is different to the given
+// manifest, and false if the object does not exists
+func (c *Client) changedSinceApplied(info *resource.Info) (changed bool, found bool, err error) {
+	current, err := resource.NewHelper(info.Client, info.Mapping).Get(info.Namespace, info.Name, false)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false, false, nil
+		}
+		return false, false, failedTo("retrieve current configuration", info, err)
+	}
+
+	accessor, err := meta.Accessor(current)
+	if err != nil {
+		return false, true, failedTo("access the current configuration", info, err)
+	}
+	lastApplied, ok := accessor.GetAnnotations()[corev1.LastAppliedConfigAnnotation]
+	if !ok {
+		return true, true, nil
+	}
+
+	var last map[string]interface{}
+	if err := json.Unmarshal([]byte(lastApplied), &last); err != nil {
+		return true, true, nil
+	}
+	expected := runtime.DeepCopyJSON(objectMap(info))
+	unstructured.RemoveNestedField(expected, "metadata", "annotations", corev1.LastAppliedConfigAnnotation)
+	unstructured.RemoveNestedField(expected, "metadata", "namespace")
+	unstructured.RemoveNestedField(last, "metadata", "namespace")
+	// The last applied configuration keeps the annotations empty if the object
+	// had no other annotation
+	for _, obj := range []map[string]interface{}{expected, last} {
+		if annotations, found, _ := unstructured.NestedMap(obj, "metadata", "annotations"); found && len(annotations) == 0 {
+			unstructured.RemoveNestedField(obj, "metadata", "annotations")
+		}
+	}
+
+	return !reflect.DeepEqual(last, expected), true, nil
+}
+
+// pruneCandidates returns the objects in current that are not in desired
+func pruneCandidates(current, desired []ObjectReference) []ObjectReference {
+	keep := make(map[string]bool, len(desired))
+	for _, obj := range desired {
+		keep[obj.key()] = true
+	}
+	var prune []ObjectReference
+	for _, obj := range current {
+		if !keep[obj.key()] {
+			prune = append(prune, obj)
+		}
+	}
+	return prune
+}
+
+// key identifies the object regardless of the version of the API
+func (o ObjectReference) key() string {
+	return strings.Join([]string{o.Group, o.Kind, o.Namespace, o.Name}, "/")
+}
+
+// encodeManifests returns the YAML documents of the given resources, with the
+// namespace they are applied to
+func encodeManifests(infos []*resource.Info) ([]byte, error) {
+	docs := make([][]byte, 0, len(infos))
+	for _, info := range infos {
+		obj := info.Object.DeepCopyObject()
+		if info.Namespaced() {
+			if accessor, err := meta.Accessor(obj); err == nil && accessor.GetNamespace() == "" {
+				accessor.SetNamespace(info.Namespace)
+			}
+		}
+		data, err := runtime.Encode(unstructured.UnstructuredJSONScheme, obj)
+		if err != nil {
+			return nil, failedTo("encode", info, err)
+		}
+		doc, err := yaml.JSONToYAML(data)
+		if err != nil {
+			return nil, failedTo("encode", info, err)
+		}
+		docs = append(docs, bytes.TrimSpace(doc))
+	}
+	return joinDocuments(docs), nil
+}
+
+// saveRevision stores the applied manifests of the release in a new Secret
+// and deletes the oldest revisions exceeding the history limit
+func (c *Client) saveRevision(release *Release, manifests []byte) error {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(manifests); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	data, err := json.Marshal(release)
+	if err != nil {
+		return err
+	}
+
+	namespace := c.releaseNamespace()
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      revisionName(release.Name, release.Revision),
+			Namespace: namespace,
+			Labels: map[string]string{
+				managedByLabel: managedBy,
+				releaseLabel:   release.Name,
+				revisionLabel:  strconv.Itoa(release.Revision),
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			releaseKey:   data,
+			manifestsKey: buf.Bytes(),
+		},
+	}
+	if _, err := c.Clientset.CoreV1().Secrets(namespace).Create(secret); err != nil {
+		return err
+	}
+
+	return c.pruneHistory(release.Name)
+}
+
+// pruneHistory deletes the oldest revisions of the release exceeding the
+// history limit
+func (c *Client) pruneHistory(name string) error {
+	limit := c.ReleaseHistory
+	if limit <= 0 {
+		limit = DefaultReleaseHistory
+	}
+	revisions, err := c.History(name)
+	if err != nil {
+		return err
+	}
+	if len(revisions) <= limit {
+		return nil
+	}
+
+	for _, revision := range revisions[:len(revisions)-limit] {
+		if err := c.deleteRevision(name, revision.Release.Revision); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteHistory deletes all the revisions of the release
+func (c *Client) deleteHistory(name string) error {
+	revisions, err := c.History(name)
+	if err != nil {
+		return err
+	}
+	for _, revision := range revisions {
+		if err := c.deleteRevision(name, revision.Release.Revision); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Client) deleteRevision(name string, revision int) error {
+	err := c.Clientset.CoreV1().Secrets(c.releaseNamespace()).Delete(revisionName(name, revision), &metav1.DeleteOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// revisionName is the name of the Secret with the given revision of a
+// release. The release names have no dots, see validateReleaseName
+func revisionName(name string, revision int) string {
+	return fmt.Sprintf("%s%s.v%d", releasePrefix, name, revision)
+}
+
+// decodeRevision returns the revision stored in the given Secret
+func decodeRevision(secret *corev1.Secret) (*Revision, error) {
+	var release Release
+	if err := json.Unmarshal(secret.Data[releaseKey], &release); err != nil {
+		return nil, fmt.Errorf("cannot decode the revision %q. %s", secret.Name, err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(secret.Data[manifestsKey]))
+	if err != nil {
+		return nil, fmt.Errorf("cannot decompress the revision %q. %s", secret.Name, err)
+	}
+	defer zr.Close()
+	manifests, err := ioutil.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decompress the revision %q. %s", secret.Name, err)
+	}
+
+	return &Revision{Release: &release, Manifests: manifests}, nil
+}
@@ -0,0 +1,233 @@
+package klient
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+func TestPruneCandidates(t *testing.T) {
+	cm := ObjectReference{Version: "v1", Kind: "ConfigMap", Namespace: "apps", Name: "cm"}
+	deployV1 := ObjectReference{Group: "apps", Version: "v1", Kind: "Deployment", Namespace: "apps", Name: "web"}
+	deployBeta := ObjectReference{Group: "apps", Version: "v1beta2", Kind: "Deployment", Namespace: "apps", Name: "web"}
+	ns := ObjectReference{Version: "v1", Kind: "Namespace", Name: "apps"}
+
+	tests := []struct {
+		name    string
+		current []ObjectReference
+		desired []ObjectReference
+		want    []ObjectReference
+	}{
+		{"nothing to prune", []ObjectReference{ns, cm}, []ObjectReference{ns, cm}, nil},
+		{"removed objects", []ObjectReference{ns, cm, deployV1}, []ObjectReference{ns}, []ObjectReference{cm, deployV1}},
+		{"other API version", []ObjectReference{deployV1}, []ObjectReference{deployBeta}, nil},
+		{"new objects", []ObjectReference{cm}, []ObjectReference{cm, deployV1}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pruneCandidates(tt.current, tt.desired); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("pruneCandidates() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodeManifests(t *testing.T) {
+	cm := testInfo(t, `{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "cm", "namespace": "apps"}, "data": {"key": "value"}}`)
+	ns := testInfo(t, `{"apiVersion": "v1", "kind": "Namespace", "metadata": {"name": "apps"}}`)
+	noNS := testInfo(t, `{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "other", "namespace": "apps"}}`)
+	noNS.Object.(*unstructured.Unstructured).SetNamespace("")
+
+	got, err := encodeManifests([]*resource.Info{ns, cm, noNS})
+	if err != nil {
+		t.Fatalf("encodeManifests() error = %v", err)
+	}
+	docs := strings.Split(string(got), "\n---\n")
+	if len(docs) != 3 {
+		t.Fatalf("encodeManifests() returned %d documents, want 3:\n%s", len(docs), got)
+	}
+	if strings.Contains(docs[0], "namespace:") {
+		t.Errorf("encodeManifests() set a namespace to a cluster-scoped object:\n%s", docs[0])
+	}
+	if !strings.Contains(docs[1], "key: value") {
+		t.Errorf("encodeManifests() lost the data of the object:\n%s", docs[1])
+	}
+	if !strings.Contains(docs[2], "namespace: apps") {
+		t.Errorf("encodeManifests() did not set the namespace of the object:\n%s", docs[2])
+	}
+	if noNS.Object.(*unstructured.Unstructured).GetNamespace() != "" {
+		t.Errorf("encodeManifests() modified the object")
+	}
+}
+
+func TestValidateReleaseName(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr bool
+	}{
+		{"app", false},
+		{"web-1", false},
+		// The inventory of `app.v1` would be the revision 1 of `app`
+		{"app.v1", true},
+		{"App", true},
+		{"", true},
+		{strings.Repeat("a", 64), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateReleaseName(tt.name); (err != nil) != tt.wantErr {
+				t.Errorf("validateReleaseName() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestClient_History(t *testing.T) {
+	tests := []struct {
+		name          string
+		history       int
+		wantRevisions []int
+	}{
+		{"default history", 0, []int{1, 2, 3}},
+		{"pruned history", 2, []int{2, 3}},
+		{"one revision", 1, []int{3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, _ := storeHandler(t)
+			c := testServerClient(t, handler)
+			c.ReleaseHistory = tt.history
+
+			applied := []string{"web", "web,db", "db"}
+			for _, names := range applied {
+				if _, err := c.ApplyRelease("app", testConfigMaps(strings.Split(names, ",")...)); err != nil {
+					t.Fatalf("Client.ApplyRelease() error = %v", err)
+				}
+			}
+			// Other releases are not in the history
+			if _, err := c.ApplyRelease("other", testConfigMaps("cache")); err != nil {
+				t.Fatalf("Client.ApplyRelease() error = %v", err)
+			}
+
+			revisions, err := c.History("app")
+			if err != nil {
+				t.Fatalf("Client.History() error = %v", err)
+			}
+			got := []int{}
+			for _, revision := range revisions {
+				got = append(got, revision.Release.Revision)
+				want := "name: " + strings.Split(applied[revision.Release.Revision-1], ",")[0]
+				if !strings.Contains(string(revision.Manifests), want) {
+					t.Errorf("Client.History() revision %d manifests = %s, want %q", revision.Release.Revision, revision.Manifests, want)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.wantRevisions) {
+				t.Errorf("Client.History() revisions = %v, want %v", got, tt.wantRevisions)
+			}
+
+			if _, err := c.GetRevision("app", tt.wantRevisions[0]); err != nil {
+				t.Errorf("Client.GetRevision() error = %v", err)
+			}
+			if tt.wantRevisions[0] > 1 {
+				if _, err := c.GetRevision("app", 1); err == nil {
+					t.Errorf("Client.GetRevision() got the pruned revision 1")
+				}
+			}
+		})
+	}
+}
+
+func TestClient_Rollback(t *testing.T) {
+	handler, stored := storeHandler(t)
+	c := testServerClient(t, handler)
+
+	if _, err := c.Rollback("app", 0); err == nil {
+		t.Errorf("Client.Rollback() of a release not found error = nil")
+	}
+
+	revision1 := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: web\ndata:\n  color: blue\n---\n" + string(testConfigMaps("db", "cache"))
+	revision2 := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: web\ndata:\n  color: green\n---\n" + string(testConfigMaps("db", "queue"))
+	for _, content := range []string{revision1, revision2} {
+		if _, err := c.ApplyRelease("app", []byte(content)); err != nil {
+			t.Fatalf("Client.ApplyRelease() error = %v", err)
+		}
+	}
+	if err := c.Delete(testConfigMaps("cache")); err != nil {
+		t.Fatalf("Client.Delete() error = %v", err)
+	}
+
+	if _, err := c.Rollback("app", 2); err == nil {
+		t.Errorf("Client.Rollback() to the current revision error = nil")
+	}
+
+	report, err := c.Rollback("app", 0)
+	if err != nil {
+		t.Fatalf("Client.Rollback() error = %v", err)
+	}
+	if report.FromRevision != 2 || report.ToRevision != 1 || report.Revision != 3 {
+		t.Errorf("Client.Rollback() revisions = %d -> %d (%d), want 2 -> 1 (3)", report.FromRevision, report.ToRevision, report.Revision)
+	}
+	for _, group := range []struct {
+		name string
+		got  []ObjectReference
+		want []string
+	}{
+		{"created", report.Created, []string{"cache"}},
+		{"changed", report.Changed, []string{"web"}},
+		{"unchanged", report.Unchanged, []string{"db"}},
+		{"pruned", report.Pruned, []string{"queue"}},
+	} {
+		if got := objectNames(group.got); !reflect.DeepEqual(got, group.want) {
+			t.Errorf("Client.Rollback() %s = %v, want %v", group.name, got, group.want)
+		}
+	}
+
+	want := []string{
+		"configmaps/cache", "configmaps/db", "configmaps/klient-release-app", "configmaps/web",
+		"secrets/klient-release-app.v1", "secrets/klient-release-app.v2", "secrets/klient-release-app.v3",
+	}
+	if got := stored(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Client.Rollback() stored = %v, want %v", got, want)
+	}
+	revision, err := c.GetRevision("app", 3)
+	if err != nil {
+		t.Fatalf("Client.GetRevision() error = %v", err)
+	}
+	if !strings.Contains(string(revision.Manifests), "color: blue") {
+		t.Errorf("Client.GetRevision() manifests = %s, want the manifests of revision 1", revision.Manifests)
+	}
+}
+
+func TestClient_Rollback_dryRun(t *testing.T) {
+	handler, stored := storeHandler(t)
+	c := testServerClient(t, handler)
+
+	for _, names := range [][]string{{"web", "db"}, {"web", "cache"}} {
+		if _, err := c.ApplyRelease("app", testConfigMaps(names...)); err != nil {
+			t.Fatalf("Client.ApplyRelease() error = %v", err)
+		}
+	}
+	want := stored()
+
+	c.DryRun = true
+	report, err := c.Rollback("app", 1)
+	if err != nil {
+		t.Fatalf("Client.Rollback() error = %v", err)
+	}
+	if got := objectNames(report.Pruned); !reflect.DeepEqual(got, []string{"cache"}) {
+		t.Errorf("Client.Rollback() pruned = %v, want [cache]", got)
+	}
+	if got := stored(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Client.Rollback() in dry-run stored = %v, want %v", got, want)
+	}
+	release, err := c.GetRelease("app")
+	if err != nil {
+		t.Fatalf("Client.GetRelease() error = %v", err)
+	}
+	if release.Revision != 2 {
+		t.Errorf("Client.GetRelease() revision = %d, want 2", release.Revision)
+	}
+}
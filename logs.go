@@ -0,0 +1,257 @@
+package klient
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/kubectl/pkg/polymorphichelpers"
+)
+
+// labelSelectorOperators are the characters of a label selector that cannot be
+// in a pod name, i.e. `app=web`, `app!=web`, `app in (web,api)` or `!canary`
+const labelSelectorOperators = "=!(), "
+
+// LogOptions are the options to get the logs of the containers
+type LogOptions struct {
+	// Container is the container to get the logs from. If empty, the logs of
+	// all the containers of the pods are returned
+	Container string
+	// Follow streams the logs until the context is done
+	Follow bool
+	// Since, if not zero, returns only the logs newer than this duration
+	Since time.Duration
+	// Tail, if not negative, is the number of lines from the end of the logs
+	Tail int64
+	// Timestamps adds the timestamp at the beginning of every line
+	Timestamps bool
+	// Previous returns the logs of the previous terminated container
+	Previous bool
+	// Prefix adds the pod and container name at the beginning of every line
+	Prefix bool
+}
+
+// NewLogOptions creates a default LogOptions to get all the logs of all the
+// containers, prefixing every line with the pod and container name
+func NewLogOptions() *LogOptions {
+	return &LogOptions{
+		Tail:   -1,
+		Prefix: true,
+	}
+}
+
+// Logs returns the logs of the containers of the pods selected in the client
+// namespace by the given target, which is a pod name, a label selector like
+// `app=web` or `app in (web,api)`, or a resource name like `deployment/web`,
+// `statefulset/db` or `job/migrate`. The logs of all the containers are
+// multiplexed line by line in the returned reader, which is closed when all
+// the logs are read or the context is done. The containers which logs cannot
+// be streamed are reported in the logs, it fails only if no container logs
+// can be streamed
+func (c *Client) Logs(ctx context.Context, selectorOrName string, opt *LogOptions) (io.ReadCloser, error) {
+	if opt == nil {
+		opt = NewLogOptions()
+	}
+
+	pods, err := c.podsFor(selectorOrName)
+	if err != nil {
+		return nil, err
+	}
+	if len(pods) == 0 {
+		return nil, fmt.Errorf("no pods found for %q", selectorOrName)
+	}
+
+	var streams []logStream
+	errs := []error{}
+	for _, pod := range pods {
+		for _, container := range logContainers(pod, opt.Container) {
+			s := logStream{}
+			if opt.Prefix {
+				s.prefix = fmt.Sprintf("[pod/%s/%s] ", pod.Name, container)
+			}
+			rc, err := c.Clientset.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, podLogOptions(container, opt)).Stream()
+			if err != nil {
+				// The containers without logs, i.e. waiting to start, are reported in
+				// the logs so the logs of the other containers are not lost
+				err = fmt.Errorf("cannot get the logs of container %q in pod %q. %s", container, pod.Name, err)
+				errs = append(errs, err)
+				rc = ioutil.NopCloser(strings.NewReader(err.Error() + "\n"))
+			}
+			s.reader = rc
+			streams = append(streams, s)
+		}
+	}
+	if len(errs) == len(streams) {
+		return nil, utilerrors.NewAggregate(errs)
+	}
+
+	return multiplexLogs(ctx, streams), nil
+}
+
+// podsFor returns the pods in the client namespace selected by the pod name,
+// label selector or resource name. A label key, like `tier`, is a pod name or,
+// if there is no such pod, an existence selector
+func (c *Client) podsFor(selectorOrName string) ([]corev1.Pod, error) {
+	pods := c.Clientset.CoreV1().Pods(c.namespace)
+
+	switch {
+	case strings.ContainsAny(selectorOrName, labelSelectorOperators):
+		selector, err := labels.Parse(selectorOrName)
+		if err != nil {
+			return nil, fmt.Errorf("invalid label selector %q. %s", selectorOrName, err)
+		}
+		list, err := pods.List(metav1.ListOptions{LabelSelector: selector.String()})
+		if err != nil {
+			return nil, err
+		}
+		return list.Items, nil
+	case strings.Contains(selectorOrName, "/"):
+		parts := strings.SplitN(selectorOrName, "/", 2)
+		info, err := c.object(parts[0], parts[1])
+		if err != nil {
+			return nil, err
+		}
+		if pod, ok := info.Object.(*corev1.Pod); ok {
+			return []corev1.Pod{*pod}, nil
+		}
+		namespace, selector, err := polymorphichelpers.SelectorsForObject(info.Object)
+		if err != nil {
+			return nil, fmt.Errorf("cannot select the pods of %q. %s", selectorOrName, err)
+		}
+		list, err := c.Clientset.CoreV1().Pods(namespace).List(metav1.ListOptions{LabelSelector: selector.String()})
+		if err != nil {
+			return nil, err
+		}
+		return list.Items, nil
+	default:
+		pod, err := pods.Get(selectorOrName, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			// There is no pod with this name, it may be the key of a label
+			if list, lerr := pods.List(metav1.ListOptions{LabelSelector: selectorOrName}); lerr == nil && len(list.Items) != 0 {
+				return list.Items, nil
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		return []corev1.Pod{*pod}, nil
+	}
+}
+
+// logContainers returns the given container or, if empty, all the init and
+// regular containers of the pod
+func logContainers(pod corev1.Pod, container string) []string {
+	if container != "" {
+		return []string{container}
+	}
+	var names []string
+	for _, c := range pod.Spec.InitContainers {
+		names = append(names, c.Name)
+	}
+	for _, c := range pod.Spec.Containers {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+func podLogOptions(container string, opt *LogOptions) *corev1.PodLogOptions {
+	logOpt := &corev1.PodLogOptions{
+		Container:  container,
+		Follow:     opt.Follow,
+		Previous:   opt.Previous,
+		Timestamps: opt.Timestamps,
+	}
+	if opt.Since > 0 {
+		seconds := int64(opt.Since.Round(time.Second).Seconds())
+		logOpt.SinceSeconds = &seconds
+	}
+	if opt.Tail >= 0 {
+		tail := opt.Tail
+		logOpt.TailLines = &tail
+	}
+	return logOpt
+}
+
+// logStream is the logs of a container and the prefix for every line
+type logStream struct {
+	prefix string
+	reader io.ReadCloser
+}
+
+// multiplexLogs copies every line of the given streams to the returned reader,
+// prefixed. The lines of different streams are not mixed
+func multiplexLogs(ctx context.Context, streams []logStream) io.ReadCloser {
+	pr, pw := io.Pipe()
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for _, s := range streams {
+		wg.Add(1)
+		go func(s logStream) {
+			defer wg.Done()
+			defer s.reader.Close()
+			r := bufio.NewReader(s.reader)
+			for {
+				line, err := r.ReadBytes('\n')
+				if len(line) != 0 {
+					if line[len(line)-1] != '\n' {
+						line = append(line, '\n')
+					}
+					mu.Lock()
+					_, werr := io.WriteString(pw, s.prefix+string(line))
+					mu.Unlock()
+					if werr != nil {
+						return
+					}
+				}
+				if err != nil {
+					return
+				}
+			}
+		}(s)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	go func() {
+		select {
+		case <-done:
+			pw.Close()
+		case <-ctx.Done():
+			// Closing the streams stop the readers
+			for _, s := range streams {
+				s.reader.Close()
+			}
+			pw.CloseWithError(ctx.Err())
+		}
+	}()
+
+	return &logsReader{PipeReader: pr, streams: streams}
+}
+
+// logsReader is the reader of the multiplexed logs. Closing it also closes
+// the logs streams
+type logsReader struct {
+	*io.PipeReader
+	streams []logStream
+}
+
+func (r *logsReader) Close() error {
+	for _, s := range r.streams {
+		s.reader.Close()
+	}
+	return r.PipeReader.Close()
+}
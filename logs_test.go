@@ -0,0 +1,203 @@
+package klient
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestMultiplexLogs(t *testing.T) {
+	tests := []struct {
+		name    string
+		streams map[string]string
+		want    []string
+	}{
+		{"single stream", map[string]string{"[pod/a/c] ": "one\ntwo\n"}, []string{"[pod/a/c] one", "[pod/a/c] two"}},
+		{"no prefix", map[string]string{"": "one\ntwo"}, []string{"one", "two"}},
+		{"multiple streams", map[string]string{"[pod/a/c] ": "one\n", "[pod/b/c] ": "two\nthree\n"}, []string{"[pod/a/c] one", "[pod/b/c] three", "[pod/b/c] two"}},
+		{"empty stream", map[string]string{"[pod/a/c] ": ""}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var streams []logStream
+			for prefix, content := range tt.streams {
+				streams = append(streams, logStream{prefix: prefix, reader: ioutil.NopCloser(strings.NewReader(content))})
+			}
+			out, err := ioutil.ReadAll(multiplexLogs(context.Background(), streams))
+			if err != nil {
+				t.Fatalf("multiplexLogs() error = %v", err)
+			}
+			var got []string
+			if len(out) != 0 {
+				got = strings.Split(strings.TrimSuffix(string(out), "\n"), "\n")
+			}
+			sort.Strings(got)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("multiplexLogs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMultiplexLogs_cancel(t *testing.T) {
+	pr, pw := io.Pipe()
+	defer pw.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	r := multiplexLogs(ctx, []logStream{{reader: pr}})
+
+	go func() {
+		pw.Write([]byte("first\n"))
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+
+	out, err := ioutil.ReadAll(r)
+	if err != context.Canceled {
+		t.Errorf("multiplexLogs() error = %v, want %v", err, context.Canceled)
+	}
+	if string(out) != "first\n" {
+		t.Errorf("multiplexLogs() = %q, want %q", out, "first\n")
+	}
+}
+
+// logsHandler is a fake API server with the pod `web` and the containers `app`,
+// which logs `hello`, and `sidecar`, which is waiting to start
+func logsHandler(w http.ResponseWriter, req *http.Request) {
+	switch req.URL.Path {
+	case "/api/v1/namespaces/test/pods/web":
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(corev1.Pod{
+			TypeMeta:   metav1.TypeMeta{Kind: "Pod", APIVersion: "v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "test"},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}, {Name: "sidecar"}}},
+		})
+	case "/api/v1/namespaces/test/pods/web/log":
+		if req.URL.Query().Get("container") != "app" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"kind": "Status", "apiVersion": "v1", "status": "Failure", "reason": "BadRequest", "code": 400,
+				"message": "container is waiting to start"}`))
+			return
+		}
+		w.Write([]byte("hello\n"))
+	default:
+		http.NotFound(w, req)
+	}
+}
+
+func TestClient_Logs(t *testing.T) {
+	c := testServerClient(t, http.HandlerFunc(logsHandler))
+
+	tests := []struct {
+		name      string
+		container string
+		want      []string
+		wantErr   bool
+	}{
+		{"container", "app", []string{"[pod/web/app] hello"}, false},
+		{"container waiting", "sidecar", nil, true},
+		{"some containers waiting", "", []string{
+			"[pod/web/app] hello",
+			`[pod/web/sidecar] cannot get the logs of container "sidecar" in pod "web". container is waiting to start`,
+		}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opt := NewLogOptions()
+			opt.Container = tt.container
+			r, err := c.Logs(context.Background(), "web", opt)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Client.Logs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			defer r.Close()
+			out, err := ioutil.ReadAll(r)
+			if err != nil {
+				t.Fatalf("Client.Logs() read error = %v", err)
+			}
+			got := strings.Split(strings.TrimSuffix(string(out), "\n"), "\n")
+			sort.Strings(got)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("Client.Logs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_podsFor(t *testing.T) {
+	var mu sync.Mutex
+	var selectors []string
+	c := testServerClient(t, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch req.URL.Path {
+		case "/api/v1/namespaces/test/pods":
+			selector := req.URL.Query().Get("labelSelector")
+			mu.Lock()
+			selectors = append(selectors, selector)
+			mu.Unlock()
+			list := corev1.PodList{}
+			if selector != "missing" {
+				list.Items = []corev1.Pod{{ObjectMeta: metav1.ObjectMeta{Name: "selected", Namespace: "test"}}}
+			}
+			json.NewEncoder(w).Encode(list)
+		case "/api/v1/namespaces/test/pods/web":
+			json.NewEncoder(w).Encode(corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "test"}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"kind": "Status", "apiVersion": "v1", "status": "Failure", "reason": "NotFound", "code": 404}`))
+		}
+	}))
+
+	tests := []struct {
+		name         string
+		target       string
+		wantPods     []string
+		wantSelector string
+		wantErr      bool
+	}{
+		{"pod name", "web", []string{"web"}, "", false},
+		{"equality selector", "app=web", []string{"selected"}, "app=web", false},
+		{"set selector", "app in (web,api)", []string{"selected"}, "app in (api,web)", false},
+		{"set selector with prefix", "app.kubernetes.io/name in (web)", []string{"selected"}, "app.kubernetes.io/name in (web)", false},
+		{"non existence selector", "!canary", []string{"selected"}, "!canary", false},
+		{"existence selector", "tier", []string{"selected"}, "tier", false},
+		{"not found", "missing", nil, "missing", true},
+		{"invalid selector", "app in (", nil, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mu.Lock()
+			selectors = nil
+			mu.Unlock()
+
+			pods, err := c.podsFor(tt.target)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Client.podsFor() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var got []string
+			for _, pod := range pods {
+				got = append(got, pod.Name)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.wantPods, ",") {
+				t.Errorf("Client.podsFor() = %v, want %v", got, tt.wantPods)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if strings.Join(selectors, ",") != tt.wantSelector {
+				t.Errorf("Client.podsFor() label selectors = %q, want %q", selectors, tt.wantSelector)
+			}
+		})
+	}
+}
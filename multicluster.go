@@ -0,0 +1,296 @@
+package klient
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+// DefaultCanaryTimeout is the time to wait for the objects in the canary
+// cluster to be ready, if MultiClient.CanaryTimeout is not set
+const DefaultCanaryTimeout = 5 * time.Minute
+
+// Cluster is a cluster of a MultiClient, with the overrides to apply to the
+// manifests for this cluster
+type Cluster struct {
+	// Name identifies the cluster in the reports
+	Name   string
+	Client *Client
+	// Namespace, if set, is the namespace of the objects in this cluster. The
+	// namespace of the objects in the manifests is rewritten
+	Namespace string
+	// Values, if set, are used to render the manifests as a Go template for
+	// this cluster, i.e. `replicas: {{ .replicas }}`
+	Values map[string]interface{}
+}
+
+// NewCluster creates a cluster with a client for the given context and
+// kubeconfig. If the name is empty, the context is the name of the cluster
+func NewCluster(name, context, kubeconfig string) (*Cluster, error) {
+	c, err := NewE(context, kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create the client for the cluster %q. %s", name, err)
+	}
+	if name == "" {
+		name = context
+	}
+	return &Cluster{Name: name, Client: c}, nil
+}
+
+// MultiClient applies or deletes the same manifests in several clusters
+// concurrently
+type MultiClient struct {
+	Clusters []*Cluster
+	// FailFast stops the clusters not started yet once a cluster fails. The
+	// clusters in progress are not interrupted. By default all the clusters are
+	// processed, best-effort
+	FailFast bool
+	// Parallelism is the maximum number of clusters processed at the same time.
+	// If zero, all the clusters are processed at the same time
+	Parallelism int
+	// Canary is the name of the cluster to apply the manifests first. The other
+	// clusters are applied once the objects in the canary cluster are ready,
+	// nothing is applied to them if the canary fails
+	Canary string
+	// CanaryTimeout is the time to wait for the objects in the canary cluster
+	// to be ready. If zero, DefaultCanaryTimeout is used
+	CanaryTimeout time.Duration
+}
+
+// NewMultiClient creates a MultiClient for the given clusters
+func NewMultiClient(clusters ...*Cluster) *MultiClient {
+	return &MultiClient{Clusters: clusters}
+}
+
+// ClusterReport is the result of an operation in a cluster
+type ClusterReport struct {
+	Cluster string
+	// Objects are the objects applied or deleted in the cluster
+	Objects []ObjectReference
+	// Skipped is true if nothing was done in the cluster because the canary,
+	// or other cluster with FailFast, failed
+	Skipped bool
+	Err     error
+}
+
+// MultiReport is the result of an operation in every cluster of a MultiClient,
+// in the same order as the clusters
+type MultiReport struct {
+	Clusters []ClusterReport
+}
+
+// Failed returns the names of the clusters that failed
+func (r *MultiReport) Failed() []string {
+	failed := []string{}
+	for _, cr := range r.Clusters {
+		if cr.Err != nil {
+			failed = append(failed, cr.Cluster)
+		}
+	}
+	return failed
+}
+
+func (r *MultiReport) String() string {
+	var b strings.Builder
+	for _, cr := range r.Clusters {
+		switch {
+		case cr.Skipped:
+			fmt.Fprintf(&b, "cluster %q skipped\n", cr.Cluster)
+		case cr.Err != nil:
+			fmt.Fprintf(&b, "cluster %q failed. %s\n", cr.Cluster, cr.Err)
+		default:
+			fmt.Fprintf(&b, "cluster %q succeeded with %d objects\n", cr.Cluster, len(cr.Objects))
+		}
+	}
+	return b.String()
+}
+
+// err returns the errors of the failed clusters
+func (r *MultiReport) err() error {
+	errs := []error{}
+	for _, cr := range r.Clusters {
+		if cr.Err != nil {
+			errs = append(errs, fmt.Errorf("cluster %q: %s", cr.Cluster, cr.Err))
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// clusterFunc is the operation done in a cluster, returns the objects of the
+// operation
+type clusterFunc func(cl *Cluster) ([]ObjectReference, error)
+
+// Apply applies the given content in every cluster, with the overrides of
+// each cluster. If there is a canary cluster it's applied first, and the rest
+// are applied once its objects are ready. The returned error aggregates the
+// errors of every failed cluster
+func (m *MultiClient) Apply(content []byte) (*MultiReport, error) {
+	if m.Canary != "" && m.cluster(m.Canary) == nil {
+		return nil, fmt.Errorf("the canary cluster %q is not one of the clusters", m.Canary)
+	}
+
+	apply := func(cl *Cluster) ([]ObjectReference, error) {
+		r, err := cl.result(content)
+		if err != nil {
+			return nil, err
+		}
+		err = cl.Client.ApplyResource(r)
+		infos, _ := r.Infos()
+		return objectReferences(infos), err
+	}
+
+	canaryTimeout := m.CanaryTimeout
+	if canaryTimeout == 0 {
+		canaryTimeout = DefaultCanaryTimeout
+	}
+	canary := func(cl *Cluster) ([]ObjectReference, error) {
+		r, err := cl.result(content)
+		if err != nil {
+			return nil, err
+		}
+		if err := cl.Client.ApplyResource(r); err != nil {
+			infos, _ := r.Infos()
+			return objectReferences(infos), err
+		}
+		infos, _ := r.Infos()
+		if notReady, err := waitForReadiness(infos, canaryTimeout); err != nil {
+			return objectReferences(infos), fmt.Errorf("the canary is not ready. %s", cl.Client.withDiagnostics(err, notReady))
+		}
+		return objectReferences(infos), nil
+	}
+
+	report := m.run(apply, canary)
+	return report, report.err()
+}
+
+// Delete deletes the objects in the given content from every cluster, with
+// the overrides of each cluster. The canary cluster, if any, is not treated
+// differently. The returned error aggregates the errors of every failed cluster
+func (m *MultiClient) Delete(content []byte) (*MultiReport, error) {
+	del := func(cl *Cluster) ([]ObjectReference, error) {
+		r, err := cl.result(content)
+		if err != nil {
+			return nil, err
+		}
+		err = cl.Client.DeleteResource(r)
+		infos, _ := r.Infos()
+		return objectReferences(infos), err
+	}
+
+	report := m.run(del, nil)
+	return report, report.err()
+}
+
+// cluster returns the cluster with the given name, nil if not found
+func (m *MultiClient) cluster(name string) *Cluster {
+	for _, cl := range m.Clusters {
+		if cl.Name == name {
+			return cl
+		}
+	}
+	return nil
+}
+
+// run does the operation in every cluster, the canary cluster first with the
+// canary operation if it's set
+func (m *MultiClient) run(fn, canaryFn clusterFunc) *MultiReport {
+	report := &MultiReport{Clusters: make([]ClusterReport, len(m.Clusters))}
+	pending := make([]int, 0, len(m.Clusters))
+	for i, cl := range m.Clusters {
+		report.Clusters[i].Cluster = cl.Name
+		if canaryFn != nil && m.Canary != "" && cl.Name == m.Canary {
+			objects, err := canaryFn(cl)
+			report.Clusters[i].Objects, report.Clusters[i].Err = objects, err
+			continue
+		}
+		pending = append(pending, i)
+	}
+
+	// Nothing else is done if the canary fails
+	if len(report.Failed()) != 0 {
+		for _, i := range pending {
+			report.Clusters[i].Skipped = true
+		}
+		return report
+	}
+
+	parallelism := m.Parallelism
+	if parallelism <= 0 {
+		parallelism = len(pending)
+	}
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	failed := false
+	sem := make(chan struct{}, parallelism)
+	for _, i := range pending {
+		sem <- struct{}{}
+		mu.Lock()
+		stop := m.FailFast && failed
+		mu.Unlock()
+		if stop {
+			<-sem
+			report.Clusters[i].Skipped = true
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			objects, err := fn(m.Clusters[i])
+			mu.Lock()
+			defer mu.Unlock()
+			report.Clusters[i].Objects, report.Clusters[i].Err = objects, err
+			if err != nil {
+				failed = true
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	return report
+}
+
+// result returns the builder results of the content with the overrides of the
+// cluster
+func (cl *Cluster) result(content []byte) (*Result, error) {
+	content, err := cl.render(content)
+	if err != nil {
+		return nil, err
+	}
+	var opt *BuilderOptions
+	if cl.Namespace != "" {
+		opt = NewBuilderOptions()
+		opt.Namespace = cl.Namespace
+		opt.NamespacePolicy = NamespacePolicyRewrite
+	}
+	r := cl.Client.ResultForContent(content, opt)
+	if err := r.Err(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// render executes the content as a Go template with the values of the cluster,
+// if there are values. The values missing in the template are an error
+func (cl *Cluster) render(content []byte) ([]byte, error) {
+	if cl.Values == nil {
+		return content, nil
+	}
+	tmpl, err := template.New(cl.Name).Option("missingkey=error").Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse the manifests for the cluster %q. %s", cl.Name, err)
+	}
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, cl.Values); err != nil {
+		return nil, fmt.Errorf("cannot render the manifests for the cluster %q. %s", cl.Name, err)
+	}
+	return b.Bytes(), nil
+}
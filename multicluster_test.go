@@ -0,0 +1,96 @@
+package klient
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestMultiClient_run(t *testing.T) {
+	ref := []ObjectReference{{Version: "v1", Kind: "ConfigMap", Name: "web"}}
+
+	tests := []struct {
+		name        string
+		clusters    []string
+		fail        map[string]bool
+		canary      string
+		failFast    bool
+		wantFailed  []string
+		wantSkipped []string
+		wantFirst   string
+	}{
+		{"all succeed", []string{"a", "b", "c"}, nil, "", false, nil, nil, ""},
+		{"best-effort", []string{"a", "b", "c"}, map[string]bool{"b": true}, "", false, []string{"b"}, nil, ""},
+		{"fail-fast", []string{"a", "b", "c"}, map[string]bool{"a": true}, "", true, []string{"a"}, []string{"b", "c"}, ""},
+		{"canary first", []string{"a", "b", "c"}, nil, "b", false, nil, nil, "b"},
+		{"canary fails", []string{"a", "b", "c"}, map[string]bool{"b": true}, "b", false, []string{"b"}, []string{"a", "c"}, "b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &MultiClient{Canary: tt.canary, FailFast: tt.failFast, Parallelism: 1}
+			for _, name := range tt.clusters {
+				m.Clusters = append(m.Clusters, &Cluster{Name: name})
+			}
+
+			var mu sync.Mutex
+			var order []string
+			fn := func(cl *Cluster) ([]ObjectReference, error) {
+				mu.Lock()
+				order = append(order, cl.Name)
+				mu.Unlock()
+				if tt.fail[cl.Name] {
+					return nil, fmt.Errorf("failed")
+				}
+				return ref, nil
+			}
+			report := m.run(fn, fn)
+
+			if got := report.Failed(); strings.Join(got, ",") != strings.Join(tt.wantFailed, ",") {
+				t.Errorf("MultiClient.run() failed = %v, want %v", got, tt.wantFailed)
+			}
+			var skipped []string
+			for _, cr := range report.Clusters {
+				if cr.Skipped {
+					skipped = append(skipped, cr.Cluster)
+				}
+				if !cr.Skipped && cr.Err == nil && len(cr.Objects) != 1 {
+					t.Errorf("MultiClient.run() objects of %q = %v, want %v", cr.Cluster, cr.Objects, ref)
+				}
+			}
+			if strings.Join(skipped, ",") != strings.Join(tt.wantSkipped, ",") {
+				t.Errorf("MultiClient.run() skipped = %v, want %v", skipped, tt.wantSkipped)
+			}
+			if tt.wantFirst != "" && order[0] != tt.wantFirst {
+				t.Errorf("MultiClient.run() order = %v, want %q first", order, tt.wantFirst)
+			}
+		})
+	}
+}
+
+func TestCluster_render(t *testing.T) {
+	content := "replicas: {{ .replicas }}"
+
+	tests := []struct {
+		name    string
+		values  map[string]interface{}
+		want    string
+		wantErr bool
+	}{
+		{"no values", nil, content, false},
+		{"values", map[string]interface{}{"replicas": 3}, "replicas: 3", false},
+		{"missing value", map[string]interface{}{"image": "nginx"}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cl := &Cluster{Name: "test", Values: tt.values}
+			got, err := cl.render([]byte(content))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Cluster.render() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Cluster.render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
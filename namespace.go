@@ -0,0 +1,139 @@
+package klient
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+// NamespacePolicy is the action to take with the objects which namespace is
+// different to the target namespace
+type NamespacePolicy string
+
+const (
+	// NamespacePolicyAllow keeps the namespace of the objects. It's the default
+	NamespacePolicyAllow NamespacePolicy = ""
+	// NamespacePolicyReject fails if an object has a namespace different to the
+	// target namespace
+	NamespacePolicyReject NamespacePolicy = "Reject"
+	// NamespacePolicyRewrite replaces the namespace of the objects with the
+	// target namespace
+	NamespacePolicyRewrite NamespacePolicy = "Rewrite"
+)
+
+// needNamespacePolicy returns true if the builder options require to enforce
+// a namespace policy on the resources
+func (opt *BuilderOptions) needNamespacePolicy() bool {
+	return opt != nil && (opt.NamespacePolicy != NamespacePolicyAllow || opt.NamespacedOnly || opt.CreateNamespace)
+}
+
+// targetNamespace returns the namespace the resources are built for
+func (c *Client) targetNamespace(opt *BuilderOptions) string {
+	if opt != nil && opt.Namespace != "" {
+		return opt.Namespace
+	}
+	return c.namespace
+}
+
+// enforceNamespacePolicy collects the resources of the result and applies the
+// namespace policy of the builder options to every one of them. If requested,
+// the target namespace is created when the resources are applied or created,
+// see createNamespace
+func (c *Client) enforceNamespacePolicy(r *Result, opt *BuilderOptions) *Result {
+	if !opt.needNamespacePolicy() || r.Err() != nil {
+		return r
+	}
+
+	target := c.targetNamespace(opt)
+
+	// Infos are cached in the result, so the visitors get the updated resources
+	infos, err := r.Infos()
+	errs := []error{}
+	if err != nil {
+		errs = append(errs, err)
+	}
+	for _, info := range infos {
+		if err := namespacePolicy(info, target, opt); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) != 0 {
+		return c.builder(opt).AddError(utilerrors.NewAggregate(errs)).Do()
+	}
+
+	if opt.CreateNamespace {
+		for _, info := range infos {
+			if info.Client != nil {
+				info.Client = namespaceCreatorClient{RESTClient: info.Client, namespace: target}
+			}
+		}
+	}
+
+	return r
+}
+
+// namespaceCreatorClient is the REST client of the resources built with
+// CreateNamespace. It keeps the target namespace with the resources, to create
+// it when they are applied or created
+type namespaceCreatorClient struct {
+	resource.RESTClient
+	namespace string
+}
+
+// createNamespace is a visit hook to create the target namespaces of the
+// resources built with CreateNamespace. Nothing is created in dry-run
+func (c *Client) createNamespace(infos []*resource.Info) error {
+	if c.DryRun {
+		return nil
+	}
+	created := map[string]bool{}
+	for _, info := range infos {
+		client, ok := info.Client.(namespaceCreatorClient)
+		if !ok || created[client.namespace] {
+			continue
+		}
+		created[client.namespace] = true
+		if err := c.createNamespaceIfNotExists(client.namespace); err != nil {
+			return fmt.Errorf("cannot create the namespace %q. %s", client.namespace, err)
+		}
+	}
+	return nil
+}
+
+func namespacePolicy(info *resource.Info, target string, opt *BuilderOptions) error {
+	if !info.Namespaced() {
+		if opt.NamespacedOnly {
+			return failedTo("accept", info, fmt.Errorf("cluster-scoped objects are not allowed"))
+		}
+		return nil
+	}
+
+	if info.Namespace == target {
+		return nil
+	}
+
+	switch opt.NamespacePolicy {
+	case NamespacePolicyReject:
+		return failedTo("accept", info, fmt.Errorf("the namespace does not match the target namespace %q", target))
+	case NamespacePolicyRewrite:
+		info.Namespace = target
+		return resource.UpdateObjectNamespace(info, nil)
+	}
+
+	return nil
+}
+
+func (c *Client) createNamespaceIfNotExists(namespace string) error {
+	_, err := c.Clientset.CoreV1().Namespaces().Get(namespace, metav1.GetOptions{})
+	if err == nil || !errors.IsNotFound(err) {
+		return err
+	}
+
+	if err := c.CreateNamespace(namespace); err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
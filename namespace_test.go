@@ -0,0 +1,111 @@
+package klient
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"sync"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestClient_NamespacePolicy(t *testing.T) {
+	envContext := os.Getenv(contextEnvVarName)
+	envKubeconfig := os.Getenv(kubeconfigEnvVarName)
+
+	cmOtherNS := []byte(`{"apiVersion": "v1", "kind": "ConfigMap", "metadata": { "name": "test-nspolicy-0", "namespace": "other" }, "data": {	"key1": "apple" } }`)
+	cmNoNS := []byte(`{"apiVersion": "v1", "kind": "ConfigMap", "metadata": { "name": "test-nspolicy-1" }, "data": {	"key1": "apple" } }`)
+	ns := []byte(`{"apiVersion": "v1", "kind": "Namespace", "metadata": { "name": "test-nspolicy-2" } }`)
+
+	tests := []struct {
+		name          string
+		content       []byte
+		opt           *BuilderOptions
+		wantNamespace string
+		wantErr       bool
+	}{
+		{"allow", cmOtherNS, &BuilderOptions{Unstructured: true, Namespace: "target"}, "other", false},
+		{"reject", cmOtherNS, &BuilderOptions{Unstructured: true, Namespace: "target", NamespacePolicy: NamespacePolicyReject}, "", true},
+		{"reject without namespace", cmNoNS, &BuilderOptions{Unstructured: true, Namespace: "target", NamespacePolicy: NamespacePolicyReject}, "target", false},
+		{"rewrite", cmOtherNS, &BuilderOptions{Unstructured: true, Namespace: "target", NamespacePolicy: NamespacePolicyRewrite}, "target", false},
+		{"namespaced only", ns, &BuilderOptions{Unstructured: true, Namespace: "target", NamespacedOnly: true}, "", true},
+		{"cluster-scoped allowed", ns, &BuilderOptions{Unstructured: true, Namespace: "target"}, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewE(envContext, envKubeconfig)
+			if err != nil {
+				t.Fatalf("failed to create the client with context %q and kubeconfig %q", envContext, envKubeconfig)
+			}
+
+			infos, err := c.ResultForContent(tt.content, tt.opt).Infos()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Client.ResultForContent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(infos) != 1 {
+				t.Fatalf("Client.ResultForContent() returned %d objects, want 1", len(infos))
+			}
+			if got := infos[0].Namespace; got != tt.wantNamespace {
+				t.Errorf("Client.ResultForContent() namespace = %q, want %q", got, tt.wantNamespace)
+			}
+		})
+	}
+}
+
+func TestClient_CreateNamespace_onVisit(t *testing.T) {
+	opt := NewBuilderOptions()
+	opt.CreateNamespace = true
+
+	tests := []struct {
+		name        string
+		dryRun      bool
+		visit       func(c *Client, r *Result) error
+		wantCreated bool
+	}{
+		{"apply", false, func(c *Client, r *Result) error { return c.ApplyResource(r) }, true},
+		{"create", false, func(c *Client, r *Result) error { return c.CreateResource(r) }, true},
+		{"apply in dry-run", true, func(c *Client, r *Result) error { return c.ApplyResource(r) }, false},
+		// The ConfigMap to delete is not found
+		{"delete", false, func(c *Client, r *Result) error { c.DeleteResource(r); return nil }, false},
+		{"diff", false, func(c *Client, r *Result) error { _, err := c.DiffResource(r); return err }, false},
+		{"build only", false, func(c *Client, r *Result) error { return r.Err() }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, _ := storeHandler(t)
+			var mu sync.Mutex
+			created := false
+			c := testServerClient(t, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				mu.Lock()
+				defer mu.Unlock()
+				switch {
+				case req.URL.Path == "/api/v1/namespaces" && req.Method == http.MethodPost:
+					created = true
+					w.Header().Set("Content-Type", "application/json")
+					w.WriteHeader(http.StatusCreated)
+					json.NewEncoder(w).Encode(v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test"}})
+				case req.URL.Path == "/api/v1/namespaces/test":
+					http.NotFound(w, req)
+				default:
+					store(w, req)
+				}
+			}))
+			c.DryRun = tt.dryRun
+
+			r := c.ResultForContent(testConfigMaps("web"), opt)
+			if err := tt.visit(c, r); err != nil {
+				t.Fatalf("visit error = %v", err)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if created != tt.wantCreated {
+				t.Errorf("namespace created = %v, want %v", created, tt.wantCreated)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package klient
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubectl/pkg/drain"
+)
+
+// Node is the state of a cluster node
+type Node struct {
+	Name  string
+	Ready bool
+	// Unschedulable is true if the node is cordoned
+	Unschedulable  bool
+	KubeletVersion string
+	Conditions     []v1.NodeCondition
+	Capacity       v1.ResourceList
+	Allocatable    v1.ResourceList
+	Taints         []v1.Taint
+}
+
+// newNode returns the state of the given node
+func newNode(n *v1.Node) Node {
+	node := Node{
+		Name:           n.Name,
+		Unschedulable:  n.Spec.Unschedulable,
+		KubeletVersion: n.Status.NodeInfo.KubeletVersion,
+		Conditions:     n.Status.Conditions,
+		Capacity:       n.Status.Capacity,
+		Allocatable:    n.Status.Allocatable,
+		Taints:         n.Spec.Taints,
+	}
+	for _, c := range n.Status.Conditions {
+		if c.Type == v1.NodeReady && c.Status == v1.ConditionTrue {
+			node.Ready = true
+			break
+		}
+	}
+	return node
+}
+
+// Nodes returns the state of the nodes matching the label selector, i.e.
+// `node-role.kubernetes.io/master`. All the nodes are returned if it's empty
+func (c *Client) Nodes(selector string) ([]Node, error) {
+	list, err := c.Clientset.CoreV1().Nodes().List(metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([]Node, 0, len(list.Items))
+	for i := range list.Items {
+		nodes = append(nodes, newNode(&list.Items[i]))
+	}
+	return nodes, nil
+}
+
+// Node returns the state of the node with the given name
+func (c *Client) Node(name string) (*Node, error) {
+	n, err := c.Clientset.CoreV1().Nodes().Get(name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	node := newNode(n)
+	return &node, nil
+}
+
+// Cordon marks the node as unschedulable, no new pods are scheduled on it
+func (c *Client) Cordon(name string) error {
+	return c.cordonOrUncordon(name, true)
+}
+
+// Uncordon marks the node as schedulable
+func (c *Client) Uncordon(name string) error {
+	return c.cordonOrUncordon(name, false)
+}
+
+func (c *Client) cordonOrUncordon(name string, unschedulable bool) error {
+	// From: k8s.io/kubectl/pkg/drain/default.go > func RunCordonOrUncordon()
+	n, err := c.Clientset.CoreV1().Nodes().Get(name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	helper := drain.NewCordonHelper(n)
+	if !helper.UpdateIfRequired(unschedulable) {
+		return nil
+	}
+	err, patchErr := helper.PatchOrReplace(c.Clientset)
+	if patchErr != nil {
+		return fmt.Errorf("cannot patch the node %q. %s", name, patchErr)
+	}
+	if err != nil {
+		return fmt.Errorf("cannot update the node %q. %s", name, err)
+	}
+	return nil
+}
+
+// DrainOptions are the parameters to drain a node
+type DrainOptions struct {
+	// GracePeriodSeconds is the time given to the pods to terminate gracefully.
+	// If nil, the grace period of every pod is used
+	GracePeriodSeconds *int
+	// Timeout is the time to wait for the pods to be evicted. If zero, it waits
+	// forever
+	Timeout time.Duration
+	// Force evicts the pods not managed by a controller, they are not recreated
+	Force bool
+	// DeleteLocalData evicts the pods using emptyDir volumes, their data is lost
+	DeleteLocalData bool
+	// PodSelector is the label selector of the pods to evict. All the pods are
+	// evicted if it's empty
+	PodSelector string
+	// Out is where the evicted pods are reported, if set
+	Out io.Writer
+	// ErrOut is where the pods that cannot be evicted are reported, if set
+	ErrOut io.Writer
+}
+
+// NewDrainOptions creates a DrainOptions with the default values, like
+// `kubectl drain`
+func NewDrainOptions() *DrainOptions {
+	return &DrainOptions{}
+}
+
+// Drain cordons the node and evicts its pods with the Eviction API, so the
+// PodDisruptionBudgets are respected. The evictions blocked by a budget are
+// retried until the timeout. The DaemonSet and mirror pods are not evicted
+func (c *Client) Drain(name string, opt *DrainOptions) error {
+	if opt == nil {
+		opt = NewDrainOptions()
+	}
+	// Deleting the pods, like kubectl does without eviction, ignores the budgets
+	gv, err := drain.CheckEvictionSupport(c.Clientset)
+	if err != nil {
+		return fmt.Errorf("cannot discover the Eviction API. %s", err)
+	}
+	if gv == "" {
+		return fmt.Errorf("cannot drain the node %q, the Eviction API is not available", name)
+	}
+	if err := c.Cordon(name); err != nil {
+		return err
+	}
+
+	out, errOut := opt.Out, opt.ErrOut
+	if out == nil {
+		out = ioutil.Discard
+	}
+	if errOut == nil {
+		errOut = ioutil.Discard
+	}
+	// The drain helper uses the grace period of every pod if it's negative
+	gracePeriodSeconds := -1
+	if opt.GracePeriodSeconds != nil {
+		gracePeriodSeconds = *opt.GracePeriodSeconds
+	}
+	helper := &drain.Helper{
+		Client:              c.Clientset,
+		Force:               opt.Force,
+		GracePeriodSeconds:  gracePeriodSeconds,
+		IgnoreAllDaemonSets: true,
+		Timeout:             opt.Timeout,
+		DeleteLocalData:     opt.DeleteLocalData,
+		PodSelector:         opt.PodSelector,
+		Out:                 out,
+		ErrOut:              errOut,
+	}
+	if err := drain.RunNodeDrain(helper, name); err != nil {
+		return fmt.Errorf("cannot drain the node %q. %s", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package klient
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewNode(t *testing.T) {
+	capacity := v1.ResourceList{v1.ResourceCPU: resource.MustParse("4"), v1.ResourcePods: resource.MustParse("110")}
+	taints := []v1.Taint{{Key: "node-role.kubernetes.io/master", Effect: v1.TaintEffectNoSchedule}}
+
+	tests := []struct {
+		name string
+		node v1.Node
+		want Node
+	}{
+		{"ready", v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "master"},
+			Spec:       v1.NodeSpec{Taints: taints},
+			Status: v1.NodeStatus{
+				Conditions:  []v1.NodeCondition{{Type: v1.NodeMemoryPressure, Status: v1.ConditionFalse}, {Type: v1.NodeReady, Status: v1.ConditionTrue}},
+				Capacity:    capacity,
+				Allocatable: capacity,
+				NodeInfo:    v1.NodeSystemInfo{KubeletVersion: "v1.17.3"},
+			},
+		}, Node{
+			Name:           "master",
+			Ready:          true,
+			KubeletVersion: "v1.17.3",
+			Conditions:     []v1.NodeCondition{{Type: v1.NodeMemoryPressure, Status: v1.ConditionFalse}, {Type: v1.NodeReady, Status: v1.ConditionTrue}},
+			Capacity:       capacity,
+			Allocatable:    capacity,
+			Taints:         taints,
+		}},
+		{"cordoned not ready", v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "worker"},
+			Spec:       v1.NodeSpec{Unschedulable: true},
+			Status:     v1.NodeStatus{Conditions: []v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionUnknown}}},
+		}, Node{
+			Name:          "worker",
+			Unschedulable: true,
+			Conditions:    []v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionUnknown}},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newNode(&tt.node); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("newNode() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// nodeHandler is a fake API server with the node `worker` running the pods
+// `web`, managed by a ReplicaSet, and `agent`, managed by a DaemonSet. It
+// records the requests that modify the node or the pods
+func nodeHandler(t *testing.T) (http.HandlerFunc, func() []string) {
+	var mu sync.Mutex
+	requests := []string{}
+	evicted := map[string]bool{}
+	controller := func(kind, name string) []metav1.OwnerReference {
+		yes := true
+		return []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: kind, Name: name, Controller: &yes}}
+	}
+	pods := []v1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "test", OwnerReferences: controller("ReplicaSet", "web")}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "test", OwnerReferences: controller("DaemonSet", "agent")}},
+	}
+
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		if req.Method != http.MethodGet {
+			requests = append(requests, req.Method+" "+req.URL.Path)
+		}
+
+		switch {
+		case req.URL.Path == "/api":
+			json.NewEncoder(w).Encode(metav1.APIVersions{Versions: []string{"v1"}})
+		case req.URL.Path == "/apis":
+			json.NewEncoder(w).Encode(metav1.APIGroupList{Groups: []metav1.APIGroup{{
+				Name:             "policy",
+				Versions:         []metav1.GroupVersionForDiscovery{{GroupVersion: "policy/v1beta1", Version: "v1beta1"}},
+				PreferredVersion: metav1.GroupVersionForDiscovery{GroupVersion: "policy/v1beta1", Version: "v1beta1"},
+			}}})
+		case req.URL.Path == "/api/v1":
+			json.NewEncoder(w).Encode(metav1.APIResourceList{GroupVersion: "v1", APIResources: []metav1.APIResource{
+				{Name: "pods", Kind: "Pod", Namespaced: true},
+				{Name: "pods/eviction", Kind: "Eviction", Namespaced: true},
+			}})
+		case req.URL.Path == "/api/v1/nodes/worker" && req.Method == http.MethodGet:
+			json.NewEncoder(w).Encode(v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker"}})
+		case req.URL.Path == "/api/v1/nodes/worker" && req.Method == http.MethodPatch:
+			body, _ := ioutil.ReadAll(req.Body)
+			requests[len(requests)-1] += " " + string(body)
+			json.NewEncoder(w).Encode(v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker"}, Spec: v1.NodeSpec{Unschedulable: true}})
+		case req.URL.Path == "/api/v1/pods":
+			if req.URL.Query().Get("fieldSelector") != "spec.nodeName=worker" {
+				t.Errorf("unexpected pods field selector %q", req.URL.Query().Get("fieldSelector"))
+			}
+			json.NewEncoder(w).Encode(v1.PodList{Items: pods})
+		case req.URL.Path == "/apis/apps/v1/namespaces/test/daemonsets/agent":
+			w.Write([]byte(`{"apiVersion": "apps/v1", "kind": "DaemonSet", "metadata": {"name": "agent", "namespace": "test"}}`))
+		case strings.HasSuffix(req.URL.Path, "/eviction"):
+			eviction := struct {
+				DeleteOptions *metav1.DeleteOptions `json:"deleteOptions"`
+			}{}
+			json.NewDecoder(req.Body).Decode(&eviction)
+			if eviction.DeleteOptions != nil && eviction.DeleteOptions.GracePeriodSeconds != nil {
+				requests[len(requests)-1] += fmt.Sprintf(" gracePeriodSeconds=%d", *eviction.DeleteOptions.GracePeriodSeconds)
+			}
+			evicted[strings.Split(req.URL.Path, "/")[6]] = true
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"kind": "Status", "apiVersion": "v1", "status": "Success"}`))
+		case strings.HasPrefix(req.URL.Path, "/api/v1/namespaces/test/pods/"):
+			name := strings.TrimPrefix(req.URL.Path, "/api/v1/namespaces/test/pods/")
+			if evicted[name] {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"kind": "Status", "apiVersion": "v1", "status": "Failure", "reason": "NotFound", "code": 404}`))
+				return
+			}
+			json.NewEncoder(w).Encode(v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test"}})
+		default:
+			http.NotFound(w, req)
+		}
+	}
+	return handler, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return requests
+	}
+}
+
+func TestClient_Drain(t *testing.T) {
+	zero, thirty := 0, 30
+	tests := []struct {
+		name               string
+		gracePeriodSeconds *int
+		wantEviction       string
+	}{
+		{"pod grace period", nil, "POST /api/v1/namespaces/test/pods/web/eviction"},
+		{"no grace period", &zero, "POST /api/v1/namespaces/test/pods/web/eviction gracePeriodSeconds=0"},
+		{"grace period", &thirty, "POST /api/v1/namespaces/test/pods/web/eviction gracePeriodSeconds=30"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, requests := nodeHandler(t)
+			c := testServerClient(t, handler)
+
+			opt := NewDrainOptions()
+			opt.GracePeriodSeconds = tt.gracePeriodSeconds
+			if err := c.Drain("worker", opt); err != nil {
+				t.Fatalf("Client.Drain() error = %v", err)
+			}
+
+			want := []string{
+				`PATCH /api/v1/nodes/worker {"spec":{"unschedulable":true}}`,
+				tt.wantEviction,
+			}
+			if got := requests(); !reflect.DeepEqual(got, want) {
+				t.Errorf("Client.Drain() requests = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestClient_Cordon(t *testing.T) {
+	handler, requests := nodeHandler(t)
+	c := testServerClient(t, handler)
+
+	if err := c.Cordon("worker"); err != nil {
+		t.Fatalf("Client.Cordon() error = %v", err)
+	}
+	// The node is schedulable already
+	if err := c.Uncordon("worker"); err != nil {
+		t.Fatalf("Client.Uncordon() error = %v", err)
+	}
+
+	want := []string{`PATCH /api/v1/nodes/worker {"spec":{"unschedulable":true}}`}
+	if got := requests(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Client.Cordon() requests = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,263 @@
+package klient
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+// PolicyRule is a rule to check the resources before they are applied, created
+// or replaced. Check returns a message for every violation of the rule
+type PolicyRule interface {
+	Name() string
+	Check(info *resource.Info) []string
+}
+
+type policyRule struct {
+	name  string
+	check func(info *resource.Info) []string
+}
+
+func (r policyRule) Name() string                       { return r.name }
+func (r policyRule) Check(info *resource.Info) []string { return r.check(info) }
+
+// NewPolicyRule creates a policy rule with the given name and check function
+func NewPolicyRule(name string, check func(info *resource.Info) []string) PolicyRule {
+	return policyRule{name: name, check: check}
+}
+
+// Policy is the set of rules to check on every resource before visit them. If
+// Audit is true the violations are reported with Warn, if set, but the
+// operation is not blocked
+type Policy struct {
+	Rules []PolicyRule
+	Audit bool
+	Warn  func(PolicyViolations)
+}
+
+// NewPolicy creates a policy that blocks the operation if any rule is violated
+func NewPolicy(rules ...PolicyRule) *Policy {
+	return &Policy{
+		Rules: rules,
+	}
+}
+
+// PolicyViolation is the violation of a rule by an object
+type PolicyViolation struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Rule      string
+	Message   string
+}
+
+func (v PolicyViolation) String() string {
+	return fmt.Sprintf("%s %s/%s violates %q: %s", v.Kind, v.Namespace, v.Name, v.Rule, v.Message)
+}
+
+// PolicyViolations is the report of the policy rules violated
+type PolicyViolations []PolicyViolation
+
+func (v PolicyViolations) Error() string {
+	msgs := make([]string, 0, len(v))
+	for _, violation := range v {
+		msgs = append(msgs, violation.String())
+	}
+	return fmt.Sprintf("policy violations: %s", strings.Join(msgs, "; "))
+}
+
+// Evaluate checks every rule of the policy on every resource, returning all
+// the violations found
+func (p *Policy) Evaluate(infos []*resource.Info) PolicyViolations {
+	var violations PolicyViolations
+	for _, info := range infos {
+		var kind string
+		if info.Mapping != nil {
+			kind = info.Mapping.GroupVersionKind.Kind
+		}
+		for _, rule := range p.Rules {
+			for _, msg := range rule.Check(info) {
+				violations = append(violations, PolicyViolation{
+					Kind:      kind,
+					Namespace: info.Namespace,
+					Name:      info.Name,
+					Rule:      rule.Name(),
+					Message:   msg,
+				})
+			}
+		}
+	}
+	return violations
+}
+
+// enforcePolicy evaluates the client policy, if any, on the resources. It's a
+// visit hook so the resources are not visited if the policy is violated
+func (c *Client) enforcePolicy(infos []*resource.Info) error {
+	if c.Policy == nil {
+		return nil
+	}
+
+	violations := c.Policy.Evaluate(infos)
+	if len(violations) == 0 {
+		return nil
+	}
+	if !c.Policy.Audit {
+		return violations
+	}
+
+	if c.Policy.Warn != nil {
+		c.Policy.Warn(violations)
+	}
+	return nil
+}
+
+// ForbidLatestTag is a rule that fails if a container image has no tag, or
+// the tag is `latest`, and no digest
+func ForbidLatestTag() PolicyRule {
+	return NewPolicyRule("forbid-latest-tag", func(info *resource.Info) []string {
+		var msgs []string
+		forEachContainer(info, func(path string, container map[string]interface{}) {
+			image, _ := container["image"].(string)
+			if strings.Contains(image, "@") {
+				return
+			}
+			tag := "latest"
+			if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+				tag = image[i+1:]
+			}
+			if tag == "latest" {
+				msgs = append(msgs, fmt.Sprintf("%s uses the image %q with the latest tag", path, image))
+			}
+		})
+		return msgs
+	})
+}
+
+// RequireResourceLimits is a rule that fails if a container does not set the
+// limits of the given resources. If no resource is given, the limits of `cpu`
+// and `memory` are required
+func RequireResourceLimits(resources ...string) PolicyRule {
+	if len(resources) == 0 {
+		resources = []string{"cpu", "memory"}
+	}
+	return NewPolicyRule("require-resource-limits", func(info *resource.Info) []string {
+		var msgs []string
+		forEachContainer(info, func(path string, container map[string]interface{}) {
+			limits, _, _ := unstructured.NestedMap(container, "resources", "limits")
+			for _, r := range resources {
+				if _, ok := limits[r]; !ok {
+					msgs = append(msgs, fmt.Sprintf("%s has no %s limit", path, r))
+				}
+			}
+		})
+		return msgs
+	})
+}
+
+// ForbidPrivileged is a rule that fails if a container is privileged
+func ForbidPrivileged() PolicyRule {
+	return NewPolicyRule("forbid-privileged", func(info *resource.Info) []string {
+		var msgs []string
+		forEachContainer(info, func(path string, container map[string]interface{}) {
+			if privileged, _, _ := unstructured.NestedBool(container, "securityContext", "privileged"); privileged {
+				msgs = append(msgs, fmt.Sprintf("%s is privileged", path))
+			}
+		})
+		return msgs
+	})
+}
+
+// ForbidHostPath is a rule that fails if a pod mounts a hostPath volume
+func ForbidHostPath() PolicyRule {
+	return NewPolicyRule("forbid-host-path", func(info *resource.Info) []string {
+		spec, path := podSpec(info)
+		if spec == nil {
+			return nil
+		}
+		var msgs []string
+		volumes, _, _ := unstructured.NestedSlice(spec, "volumes")
+		for i, v := range volumes {
+			volume, _ := v.(map[string]interface{})
+			if _, ok := volume["hostPath"]; ok {
+				msgs = append(msgs, fmt.Sprintf("%s.volumes[%d] %q is a hostPath volume", path, i, volume["name"]))
+			}
+		}
+		return msgs
+	})
+}
+
+// RequireLabels is a rule that fails if the object does not have the given labels
+func RequireLabels(labels ...string) PolicyRule {
+	return NewPolicyRule("require-labels", func(info *resource.Info) []string {
+		obj := objectMap(info)
+		if obj == nil {
+			return nil
+		}
+		current, _, _ := unstructured.NestedStringMap(obj, "metadata", "labels")
+		var msgs []string
+		for _, label := range labels {
+			if _, ok := current[label]; !ok {
+				msgs = append(msgs, fmt.Sprintf("the label %q is missing", label))
+			}
+		}
+		return msgs
+	})
+}
+
+// objectMap returns the content of the object of the resource
+func objectMap(info *resource.Info) map[string]interface{} {
+	if info.Object == nil {
+		return nil
+	}
+	return unstructuredContent(info.Object)
+}
+
+// podSpecPaths are the paths to the pod spec of the workloads, by Kind
+var podSpecPaths = map[string][]string{
+	"Pod":                   {"spec"},
+	"Deployment":            {"spec", "template", "spec"},
+	"StatefulSet":           {"spec", "template", "spec"},
+	"DaemonSet":             {"spec", "template", "spec"},
+	"ReplicaSet":            {"spec", "template", "spec"},
+	"ReplicationController": {"spec", "template", "spec"},
+	"Job":                   {"spec", "template", "spec"},
+	"CronJob":               {"spec", "jobTemplate", "spec", "template", "spec"},
+}
+
+// podSpec returns the pod spec of the workload and its path, or nil if the
+// object is not a workload
+func podSpec(info *resource.Info) (map[string]interface{}, string) {
+	obj := objectMap(info)
+	if obj == nil {
+		return nil, ""
+	}
+	kind, _ := obj["kind"].(string)
+	fields, ok := podSpecPaths[kind]
+	if !ok {
+		return nil, ""
+	}
+	spec, found, _ := unstructured.NestedMap(obj, fields...)
+	if !found {
+		return nil, ""
+	}
+	return spec, strings.Join(fields, ".")
+}
+
+// forEachContainer calls the function with every container and init container
+// of the workload, with the path to the container
+func forEachContainer(info *resource.Info, fn func(path string, container map[string]interface{})) {
+	spec, path := podSpec(info)
+	if spec == nil {
+		return
+	}
+	for _, field := range []string{"initContainers", "containers"} {
+		containers, _, _ := unstructured.NestedSlice(spec, field)
+		for i, c := range containers {
+			if container, ok := c.(map[string]interface{}); ok {
+				fn(fmt.Sprintf("%s.%s[%d]", path, field, i), container)
+			}
+		}
+	}
+}
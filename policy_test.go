@@ -0,0 +1,116 @@
+package klient
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+func testInfo(t *testing.T, content string) *resource.Info {
+	obj := &unstructured.Unstructured{}
+	if err := json.Unmarshal([]byte(content), &obj.Object); err != nil {
+		t.Fatalf("invalid test object. %s", err)
+	}
+	gvk := obj.GroupVersionKind()
+	scope := meta.RESTScopeRoot
+	if obj.GetNamespace() != "" {
+		scope = meta.RESTScopeNamespace
+	}
+	return &resource.Info{
+		Name:      obj.GetName(),
+		Namespace: obj.GetNamespace(),
+		Object:    obj,
+		Mapping: &meta.RESTMapping{
+			GroupVersionKind: gvk,
+			Resource:         schema.GroupVersionResource{Group: gvk.Group, Version: gvk.Version},
+			Scope:            scope,
+		},
+	}
+}
+
+func TestPolicy_Evaluate(t *testing.T) {
+	deployment := `{"apiVersion": "apps/v1", "kind": "Deployment", "metadata": {"name": "web", "namespace": "apps", "labels": {"app": "web"}},
+		"spec": {"template": {"spec": {
+			"initContainers": [{"name": "init", "image": "busybox", "resources": {"limits": {"cpu": "1", "memory": "1Gi"}}}],
+			"containers": [{"name": "nginx", "image": "nginx:1.17", "securityContext": {"privileged": true}, "resources": {"limits": {"cpu": "1"}}},
+				{"name": "sidecar", "image": "registry:5000/sidecar@sha256:abc", "resources": {"limits": {"cpu": "1", "memory": "1Gi"}}}],
+			"volumes": [{"name": "data", "hostPath": {"path": "/data"}}, {"name": "tmp", "emptyDir": {}}]
+		}}}}`
+	configMap := `{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "cm", "namespace": "apps"}}`
+
+	tests := []struct {
+		name    string
+		content string
+		rule    PolicyRule
+		want    []string
+	}{
+		{"latest tag", deployment, ForbidLatestTag(), []string{`spec.template.spec.initContainers[0] uses the image "busybox" with the latest tag`}},
+		{"resource limits", deployment, RequireResourceLimits(), []string{"spec.template.spec.containers[0] has no memory limit"}},
+		{"privileged", deployment, ForbidPrivileged(), []string{"spec.template.spec.containers[0] is privileged"}},
+		{"hostPath", deployment, ForbidHostPath(), []string{`spec.template.spec.volumes[0] "data" is a hostPath volume`}},
+		{"labels", deployment, RequireLabels("app", "team"), []string{`the label "team" is missing`}},
+		{"not a workload", configMap, ForbidLatestTag(), nil},
+		{"custom", configMap, NewPolicyRule("no-configmaps", func(info *resource.Info) []string {
+			if info.Mapping.GroupVersionKind.Kind == "ConfigMap" {
+				return []string{"ConfigMaps are not allowed"}
+			}
+			return nil
+		}), []string{"ConfigMaps are not allowed"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := testInfo(t, tt.content)
+			violations := NewPolicy(tt.rule).Evaluate([]*resource.Info{info})
+
+			var got []string
+			for _, v := range violations {
+				if v.Rule != tt.rule.Name() || v.Name != info.Name || v.Namespace != info.Namespace || v.Kind != info.Mapping.GroupVersionKind.Kind {
+					t.Errorf("Policy.Evaluate() violation %+v does not match the object or rule", v)
+				}
+				got = append(got, v.Message)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Policy.Evaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_enforcePolicy(t *testing.T) {
+	info := testInfo(t, `{"apiVersion": "v1", "kind": "Pod", "metadata": {"name": "p"}, "spec": {"containers": [{"name": "c", "image": "nginx:latest"}]}}`)
+
+	var warned PolicyViolations
+	tests := []struct {
+		name       string
+		policy     *Policy
+		wantErr    bool
+		wantWarned int
+	}{
+		{"no policy", nil, false, 0},
+		{"blocking", NewPolicy(ForbidLatestTag()), true, 0},
+		{"audit", &Policy{Rules: []PolicyRule{ForbidLatestTag()}, Audit: true, Warn: func(v PolicyViolations) { warned = v }}, false, 1},
+		{"audit without warn", &Policy{Rules: []PolicyRule{ForbidLatestTag()}, Audit: true}, false, 0},
+		{"passing", NewPolicy(ForbidPrivileged()), false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warned = nil
+			c := &Client{Policy: tt.policy}
+			err := c.enforcePolicy([]*resource.Info{info})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.enforcePolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if _, ok := err.(PolicyViolations); err != nil && !ok {
+				t.Errorf("Client.enforcePolicy() error = %v, want PolicyViolations", err)
+			}
+			if len(warned) != tt.wantWarned {
+				t.Errorf("Client.enforcePolicy() warned %d violations, want %d", len(warned), tt.wantWarned)
+			}
+		})
+	}
+}
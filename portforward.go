@@ -0,0 +1,261 @@
+package klient
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+)
+
+// reconnectInterval is the time to wait before reconnect a lost port-forward
+var reconnectInterval = time.Second
+
+// ForwardedPort is an alias for the client-go portforward.ForwardedPort, the
+// local port bound and the remote port it's forwarded to
+type ForwardedPort = portforward.ForwardedPort
+
+// PortForward forwards the local ports to the ports of the target, like
+// `kubectl port-forward`. The target is a pod name, or a resource name like
+// `pod/web`, `service/web` or `deployment/web` resolved to a ready pod. The
+// ports are in the format `[LOCAL_PORT:]REMOTE_PORT`, if the local port is
+// `0`, or it's empty like in `:80`, a random port is used. For services, the
+// remote ports are the service ports. Returns the bound local ports and a
+// function to stop the forwarding. If the connection is lost, i.e. the pod
+// is restarted, the target is resolved again and the same local ports are
+// forwarded until the context is done or the stop function is called
+func (c *Client) PortForward(ctx context.Context, target string, ports []string) ([]ForwardedPort, func(), error) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	pod, podPorts, err := c.portForwardTarget(target, ports)
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
+	fw, done, err := c.forwardPorts(ctx, pod, podPorts)
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
+	forwarded, err := fw.GetPorts()
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
+
+	// Reconnect using the bound local ports, the remote ports are resolved again
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-done:
+			}
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(reconnectInterval):
+				}
+				pod, podPorts, err := c.portForwardTarget(target, ports)
+				if err != nil {
+					continue
+				}
+				for i := range podPorts {
+					remote := podPorts[i][strings.LastIndex(podPorts[i], ":")+1:]
+					podPorts[i] = fmt.Sprintf("%d:%s", forwarded[i].Local, remote)
+				}
+				if _, done, err = c.forwardPorts(ctx, pod, podPorts); err == nil {
+					break
+				}
+			}
+		}
+	}()
+
+	stop := func() {
+		cancel()
+		<-stopped
+	}
+	return forwarded, stop, nil
+}
+
+// forwardPorts starts to forward the ports to the pod, returning once the
+// ports are ready. The returned channel is closed when the forwarding stops
+func (c *Client) forwardPorts(ctx context.Context, pod *corev1.Pod, ports []string) (*portforward.PortForwarder, <-chan struct{}, error) {
+	config, err := c.factory.ToRESTConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+	transport, upgrader, err := spdy.RoundTripperFor(config)
+	if err != nil {
+		return nil, nil, err
+	}
+	req := c.Clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(pod.Namespace).
+		Name(pod.Name).
+		SubResource("portforward")
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, "POST", req.URL())
+
+	stopCh := make(chan struct{})
+	readyCh := make(chan struct{})
+	fw, err := portforward.New(dialer, ports, stopCh, readyCh, ioutil.Discard, ioutil.Discard)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	done := make(chan struct{})
+	errCh := make(chan error, 1)
+	go func() {
+		defer close(done)
+		errCh <- fw.ForwardPorts()
+	}()
+	go func() {
+		select {
+		case <-ctx.Done():
+			close(stopCh)
+		case <-done:
+		}
+	}()
+
+	select {
+	case <-readyCh:
+		return fw, done, nil
+	case err := <-errCh:
+		if err == nil {
+			err = fmt.Errorf("the connection was closed")
+		}
+		return nil, nil, fmt.Errorf("cannot forward the ports of the pod %q. %s", pod.Name, err)
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	}
+}
+
+// portForwardTarget returns the ready pod of the target and the ports of the
+// pod to forward to
+func (c *Client) portForwardTarget(target string, ports []string) (*corev1.Pod, []string, error) {
+	kind, name := "pod", target
+	if i := strings.Index(target, "/"); i != -1 {
+		kind, name = strings.ToLower(target[:i]), target[i+1:]
+	}
+
+	switch kind {
+	case "pod", "pods", "po":
+		pod, err := c.Clientset.CoreV1().Pods(c.namespace).Get(name, metav1.GetOptions{})
+		if err != nil {
+			return nil, nil, err
+		}
+		if pod.Status.Phase != corev1.PodRunning {
+			return nil, nil, fmt.Errorf("the pod %q is not running, the status is %s", pod.Name, pod.Status.Phase)
+		}
+		return pod, ports, nil
+	case "service", "services", "svc":
+		svc, err := c.Clientset.CoreV1().Services(c.namespace).Get(name, metav1.GetOptions{})
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(svc.Spec.Selector) == 0 {
+			return nil, nil, fmt.Errorf("the service %q has no selector", name)
+		}
+		pods, err := c.Clientset.CoreV1().Pods(c.namespace).List(metav1.ListOptions{
+			LabelSelector: labels.SelectorFromSet(svc.Spec.Selector).String(),
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		pod, err := readyPod(target, pods.Items)
+		if err != nil {
+			return nil, nil, err
+		}
+		podPorts, err := servicePortsToPodPorts(svc, pod, ports)
+		return pod, podPorts, err
+	default:
+		pods, err := c.podsFor(target)
+		if err != nil {
+			return nil, nil, err
+		}
+		pod, err := readyPod(target, pods)
+		return pod, ports, err
+	}
+}
+
+// readyPod returns the first running and ready pod
+func readyPod(target string, pods []corev1.Pod) (*corev1.Pod, error) {
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil {
+			continue
+		}
+		for _, cond := range pod.Status.Conditions {
+			if cond.Type == corev1.PodReady && cond.Status == corev1.ConditionTrue {
+				return pod, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("no ready pods found for %q", target)
+}
+
+// servicePortsToPodPorts translates the remote service ports to the target
+// ports of the pod, keeping the local ports
+func servicePortsToPodPorts(svc *corev1.Service, pod *corev1.Pod, ports []string) ([]string, error) {
+	podPorts := make([]string, 0, len(ports))
+	for _, p := range ports {
+		local, remote := "", p
+		if i := strings.LastIndex(p, ":"); i != -1 {
+			local, remote = p[:i], p[i+1:]
+		} else {
+			local = p
+		}
+
+		svcPort, err := findServicePort(svc, remote)
+		if err != nil {
+			return nil, err
+		}
+		targetPort, err := containerPort(pod, svcPort)
+		if err != nil {
+			return nil, err
+		}
+		podPorts = append(podPorts, fmt.Sprintf("%s:%d", local, targetPort))
+	}
+	return podPorts, nil
+}
+
+func findServicePort(svc *corev1.Service, port string) (corev1.ServicePort, error) {
+	number, err := strconv.Atoi(port)
+	for _, sp := range svc.Spec.Ports {
+		if (err == nil && int(sp.Port) == number) || (err != nil && sp.Name == port) {
+			return sp, nil
+		}
+	}
+	return corev1.ServicePort{}, fmt.Errorf("the service %q does not have the port %s", svc.Name, port)
+}
+
+// containerPort returns the port of the pod the service port targets
+func containerPort(pod *corev1.Pod, svcPort corev1.ServicePort) (int32, error) {
+	switch {
+	case svcPort.TargetPort.Type == intstr.Int && svcPort.TargetPort.IntVal == 0:
+		return svcPort.Port, nil
+	case svcPort.TargetPort.Type == intstr.Int:
+		return svcPort.TargetPort.IntVal, nil
+	}
+	for _, container := range pod.Spec.Containers {
+		for _, port := range container.Ports {
+			if port.Name == svcPort.TargetPort.StrVal {
+				return port.ContainerPort, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("the pod %q does not have the port %q", pod.Name, svcPort.TargetPort.StrVal)
+}
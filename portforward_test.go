@@ -0,0 +1,131 @@
+package klient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/apimachinery/pkg/util/httpstream/spdy"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// portForwardHandler is a fake API server with a running pod `web` which
+// port-forward echoes the received data
+func portForwardHandler(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		switch {
+		case req.Method == "GET" && strings.HasSuffix(req.URL.Path, "/namespaces/test/pods/web"):
+			pod := corev1.Pod{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "test"},
+				Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(pod)
+		case req.Method == "POST" && strings.HasSuffix(req.URL.Path, "/namespaces/test/pods/web/portforward"):
+			if _, err := httpstream.Handshake(req, w, []string{"portforward.k8s.io"}); err != nil {
+				t.Errorf("failed the port-forward handshake. %s", err)
+				return
+			}
+			conn := spdy.NewResponseUpgrader().UpgradeResponse(w, req, func(s httpstream.Stream, replySent <-chan struct{}) error {
+				if s.Headers().Get(corev1.StreamType) == corev1.StreamTypeData {
+					go func() {
+						<-replySent
+						io.Copy(s, s)
+						s.Close()
+					}()
+				}
+				return nil
+			})
+			if conn == nil {
+				return
+			}
+			<-conn.CloseChan()
+		default:
+			http.NotFound(w, req)
+		}
+	}
+}
+
+func TestClient_PortForward(t *testing.T) {
+	c := testServerClient(t, portForwardHandler(t))
+
+	ports, stop, err := c.PortForward(context.Background(), "pod/web", []string{":80"})
+	if err != nil {
+		t.Fatalf("Client.PortForward() error = %v", err)
+	}
+	defer stop()
+	if len(ports) != 1 || ports[0].Remote != 80 || ports[0].Local == 0 {
+		t.Fatalf("Client.PortForward() ports = %+v, want a random local port forwarded to 80", ports)
+	}
+
+	conn, err := net.Dial("tcp", fmt.Sprintf("localhost:%d", ports[0].Local))
+	if err != nil {
+		t.Fatalf("failed to connect to the forwarded port. %s", err)
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte("hello")); err != nil {
+		t.Fatalf("failed to write to the forwarded port. %s", err)
+	}
+	got := make([]byte, 5)
+	if _, err := io.ReadFull(conn, got); err != nil {
+		t.Fatalf("failed to read from the forwarded port. %s", err)
+	}
+	if string(got) != "hello" {
+		t.Errorf("Client.PortForward() received %q, want %q", got, "hello")
+	}
+
+	if _, _, err := c.PortForward(context.Background(), "pod/db", []string{":80"}); err == nil {
+		t.Errorf("Client.PortForward() expected an error for a missing pod")
+	}
+}
+
+func TestServicePortsToPodPorts(t *testing.T) {
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "web"},
+		Spec: corev1.ServiceSpec{Ports: []corev1.ServicePort{
+			{Name: "http", Port: 80, TargetPort: intstr.FromString("http")},
+			{Name: "metrics", Port: 9090, TargetPort: intstr.FromInt(9091)},
+			{Name: "admin", Port: 8443},
+		}},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-0"},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{
+			{Name: "web", Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: 8080}}},
+		}},
+	}
+
+	tests := []struct {
+		name    string
+		ports   []string
+		want    []string
+		wantErr bool
+	}{
+		{"named target port", []string{"80"}, []string{"80:8080"}, false},
+		{"numeric target port", []string{"9000:9090"}, []string{"9000:9091"}, false},
+		{"no target port", []string{":8443"}, []string{":8443"}, false},
+		{"port name", []string{"8000:metrics"}, []string{"8000:9091"}, false},
+		{"unknown port", []string{"81"}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := servicePortsToPodPorts(svc, pod, tt.ports)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("servicePortsToPodPorts() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("servicePortsToPodPorts() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
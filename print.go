@@ -0,0 +1,337 @@
+package klient
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/client-go/util/jsonpath"
+	"k8s.io/kubectl/pkg/scheme"
+)
+
+// tableAccept requests the objects as server-side tables, like `kubectl get`
+const tableAccept = "application/json;as=Table;v=v1;g=meta.k8s.io,application/json;as=Table;v=v1beta1;g=meta.k8s.io,application/json"
+
+// printFormats are the output formats accepted by NewPrinter
+var printFormats = []string{"json", "yaml", "name", "jsonpath=", "jsonpath-file=", "go-template=", "go-template-file=", "template=", "templatefile=", "custom-columns=", "custom-columns-file="}
+
+// NewPrinter returns the printer for the output format, as in `kubectl get -o`.
+// The formats are: json, yaml, name, jsonpath=TEMPLATE, jsonpath-file=FILE,
+// go-template=TEMPLATE, go-template-file=FILE, custom-columns=SPEC and
+// custom-columns-file=FILE. The tables, the default output of kubectl, are
+// printed by the server so use Client.Print for them
+func NewPrinter(output string) (printers.ResourcePrinter, error) {
+	format, arg := output, ""
+	if i := strings.Index(output, "="); i != -1 {
+		format, arg = output[:i], output[i+1:]
+	}
+	if strings.HasSuffix(format, "-file") || format == "templatefile" {
+		content, err := ioutil.ReadFile(arg)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read the template file %q. %s", arg, err)
+		}
+		arg = string(content)
+	}
+
+	var printer printers.ResourcePrinter
+	switch format {
+	case "json":
+		printer = &printers.JSONPrinter{}
+	case "yaml":
+		printer = &printers.YAMLPrinter{}
+	case "name":
+		return &printers.NamePrinter{}, nil
+	case "jsonpath", "jsonpath-file":
+		p, err := printers.NewJSONPathPrinter(arg)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse the jsonpath template %q. %s", arg, err)
+		}
+		p.AllowMissingKeys(true)
+		printer = p
+	case "go-template", "go-template-file", "template", "templatefile":
+		p, err := printers.NewGoTemplatePrinter([]byte(arg))
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse the go-template %q. %s", arg, err)
+		}
+		p.AllowMissingKeys(true)
+		printer = p
+	case "custom-columns":
+		p, err := newCustomColumnsPrinter(arg)
+		if err != nil {
+			return nil, err
+		}
+		return p, nil
+	case "custom-columns-file":
+		p, err := newCustomColumnsPrinterFromTemplate(arg)
+		if err != nil {
+			return nil, err
+		}
+		return p, nil
+	default:
+		return nil, fmt.Errorf("cannot print with the output format %q, the allowed formats are: %s", output, strings.Join(printFormats, ", "))
+	}
+	if arg == "" && format != "json" && format != "yaml" {
+		return nil, fmt.Errorf("the output format %q requires a template", output)
+	}
+
+	// The typed objects lose the apiVersion and kind when decoded
+	return printers.NewTypeSetter(scheme.Scheme).ToPrinter(printer), nil
+}
+
+// PrintObjects prints the objects in the output format of NewPrinter to the
+// writer. Like kubectl, multiple objects are printed as a single v1 List
+func PrintObjects(w io.Writer, output string, objs ...runtime.Object) error {
+	printer, err := NewPrinter(output)
+	if err != nil {
+		return err
+	}
+	if len(objs) == 1 {
+		return printer.PrintObj(objs[0], w)
+	}
+	list, err := listOf(objs)
+	if err != nil {
+		return err
+	}
+	return printer.PrintObj(list, w)
+}
+
+// Print prints the objects of the resources to the writer in the output
+// format of `kubectl get -o`. If the output is empty or `wide`, the objects
+// are requested to the server as tables, and printed like `kubectl get`
+func (c *Client) Print(w io.Writer, output string, infos ...*resource.Info) error {
+	if output != "" && output != "wide" {
+		objs := make([]runtime.Object, 0, len(infos))
+		for _, info := range infos {
+			objs = append(objs, info.Object)
+		}
+		return PrintObjects(w, output, objs...)
+	}
+
+	// Print a table for each kind, in the order they are first found
+	var kinds []schema.GroupKind
+	tables := map[schema.GroupKind]*metav1.Table{}
+	for _, info := range infos {
+		table, err := c.table(info)
+		if err != nil {
+			return err
+		}
+		gk := info.Mapping.GroupVersionKind.GroupKind()
+		if t, ok := tables[gk]; ok {
+			t.Rows = append(t.Rows, table.Rows...)
+			continue
+		}
+		kinds = append(kinds, gk)
+		tables[gk] = table
+	}
+
+	for i, gk := range kinds {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		printer := printers.NewTablePrinter(printers.PrintOptions{
+			Wide:     output == "wide",
+			WithKind: len(kinds) > 1,
+			Kind:     gk,
+		})
+		if err := printer.PrintObj(tables[gk], w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// table requests the object of the resource to the server as a table
+func (c *Client) table(info *resource.Info) (*metav1.Table, error) {
+	client, err := c.factory.ClientForMapping(info.Mapping)
+	if err != nil {
+		return nil, err
+	}
+	data, err := client.Get().
+		NamespaceIfScoped(info.Namespace, info.Namespaced()).
+		Resource(info.Mapping.Resource.Resource).
+		Name(info.Name).
+		SetHeader("Accept", tableAccept).
+		Do().
+		Raw()
+	if err != nil {
+		return nil, fmt.Errorf("cannot get the table of %s %q. %s", info.Mapping.GroupVersionKind.Kind, info.Name, err)
+	}
+
+	table := &metav1.Table{}
+	if err := json.Unmarshal(data, table); err != nil {
+		return nil, fmt.Errorf("cannot decode the table of %s %q. %s", info.Mapping.GroupVersionKind.Kind, info.Name, err)
+	}
+	if table.Kind != "Table" {
+		return nil, fmt.Errorf("the server doesn't support tables for %s %q", info.Mapping.GroupVersionKind.Kind, info.Name)
+	}
+	return table, nil
+}
+
+// listOf returns the objects as a v1 List
+func listOf(objs []runtime.Object) (*unstructured.UnstructuredList, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetAPIVersion("v1")
+	list.SetKind("List")
+	for _, obj := range objs {
+		content := unstructuredContent(obj)
+		if content == nil {
+			return nil, fmt.Errorf("cannot print the object of type %T", obj)
+		}
+		item := unstructured.Unstructured{Object: content}
+		if item.GetKind() == "" {
+			if gvks, _, err := scheme.Scheme.ObjectKinds(obj); err == nil && len(gvks) > 0 {
+				item.SetGroupVersionKind(gvks[0])
+			}
+		}
+		list.Items = append(list.Items, item)
+	}
+	return list, nil
+}
+
+// customColumn is a column of the custom-columns output
+type customColumn struct {
+	header string
+	parser *jsonpath.JSONPath
+}
+
+// customColumnsPrinter prints the objects as a table with the columns defined
+// by JSONPath expressions, like `kubectl get -o custom-columns`
+type customColumnsPrinter struct {
+	columns []customColumn
+}
+
+var _ printers.ResourcePrinter = &customColumnsPrinter{}
+
+// newCustomColumnsPrinter creates a custom columns printer from the spec in the
+// format `HEADER:JSONPATH[,HEADER:JSONPATH...]`
+func newCustomColumnsPrinter(spec string) (*customColumnsPrinter, error) {
+	if len(spec) == 0 {
+		return nil, fmt.Errorf("custom-columns format specified but no custom columns given")
+	}
+	var headers, fields []string
+	for _, part := range strings.Split(spec, ",") {
+		column := strings.SplitN(part, ":", 2)
+		if len(column) != 2 {
+			return nil, fmt.Errorf("unexpected custom-columns spec: %s, expected <header>:<json-path-expr>", part)
+		}
+		headers = append(headers, column[0])
+		fields = append(fields, column[1])
+	}
+	return customColumns(headers, fields)
+}
+
+// newCustomColumnsPrinterFromTemplate creates a custom columns printer from a
+// template with the headers in the first line and the JSONPath expressions in
+// the second, separated by whitespaces
+func newCustomColumnsPrinterFromTemplate(template string) (*customColumnsPrinter, error) {
+	scanner := bufio.NewScanner(strings.NewReader(template))
+	var lines [][]string
+	for scanner.Scan() && len(lines) < 2 {
+		if line := strings.Fields(scanner.Text()); len(line) > 0 {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) != 2 {
+		return nil, fmt.Errorf("invalid custom-columns template, expected two lines, the headers and the JSONPath expressions")
+	}
+	if len(lines[0]) != len(lines[1]) {
+		return nil, fmt.Errorf("invalid custom-columns template, there are %d headers and %d JSONPath expressions", len(lines[0]), len(lines[1]))
+	}
+	return customColumns(lines[0], lines[1])
+}
+
+func customColumns(headers, fields []string) (*customColumnsPrinter, error) {
+	p := &customColumnsPrinter{}
+	for i, field := range fields {
+		expr, err := relaxedJSONPathExpression(field)
+		if err != nil {
+			return nil, err
+		}
+		parser := jsonpath.New(headers[i]).AllowMissingKeys(true)
+		if err := parser.Parse(expr); err != nil {
+			return nil, fmt.Errorf("cannot parse the JSONPath expression %q. %s", field, err)
+		}
+		p.columns = append(p.columns, customColumn{header: headers[i], parser: parser})
+	}
+	return p, nil
+}
+
+// PrintObj prints the object, or the items of the list, as rows of the columns
+func (p *customColumnsPrinter) PrintObj(obj runtime.Object, out io.Writer) error {
+	w := printers.GetNewTabWriter(out)
+	defer w.Flush()
+
+	headers := make([]string, 0, len(p.columns))
+	for _, column := range p.columns {
+		headers = append(headers, column.header)
+	}
+	fmt.Fprintln(w, strings.Join(headers, "\t"))
+
+	objs := []runtime.Object{obj}
+	if meta.IsListType(obj) {
+		items, err := meta.ExtractList(obj)
+		if err != nil {
+			return err
+		}
+		objs = items
+	}
+	for _, obj := range objs {
+		row, err := p.row(obj)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	return nil
+}
+
+// row returns the values of the columns for the object
+func (p *customColumnsPrinter) row(obj runtime.Object) ([]string, error) {
+	content := unstructuredContent(obj)
+	row := make([]string, 0, len(p.columns))
+	for _, column := range p.columns {
+		results, err := column.parser.FindResults(content)
+		if err != nil {
+			return nil, err
+		}
+		if len(results) == 0 || len(results[0]) == 0 {
+			row = append(row, "<none>")
+			continue
+		}
+		var values []string
+		for _, r := range results {
+			for _, v := range r {
+				values = append(values, fmt.Sprintf("%v", v.Interface()))
+			}
+		}
+		row = append(row, strings.Join(values, ","))
+	}
+	return row, nil
+}
+
+var jsonPathRegexp = regexp.MustCompile(`^\{\.?([^{}]+)\}$|^\.?([^{}]+)$`)
+
+// relaxedJSONPathExpression accepts the JSONPath expressions `a.b`, `.a.b`,
+// `{a.b}` and `{.a.b}`, returning them as `{.a.b}`
+func relaxedJSONPathExpression(expr string) (string, error) {
+	matches := jsonPathRegexp.FindStringSubmatch(expr)
+	if matches == nil {
+		return "", fmt.Errorf("unexpected path string %q, expected a 'name1.name2' or '.name1.name2' or '{name1.name2}' or '{.name1.name2}'", expr)
+	}
+	field := matches[1]
+	if field == "" {
+		field = matches[2]
+	}
+	return fmt.Sprintf("{.%s}", field), nil
+}
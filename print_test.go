@@ -0,0 +1,131 @@
+package klient
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+func TestPrintObjects(t *testing.T) {
+	web := testInfo(t, `{"apiVersion": "v1", "kind": "Pod", "metadata": {"name": "web", "namespace": "apps", "labels": {"app": "web"}}, "spec": {"containers": [{"name": "nginx", "image": "nginx"}, {"name": "proxy", "image": "envoy"}]}}`).Object
+	db := testInfo(t, `{"apiVersion": "apps/v1", "kind": "Deployment", "metadata": {"name": "db", "namespace": "apps"}}`).Object
+	typed := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config"}}
+
+	tests := []struct {
+		name    string
+		output  string
+		objs    []runtime.Object
+		want    string
+		wantErr bool
+	}{
+		{"name", "name", []runtime.Object{web, db}, "pod/web\ndeployment.apps/db\n", false},
+		{"jsonpath", "jsonpath={.metadata.name}", []runtime.Object{web}, "web", false},
+		{"jsonpath list", "jsonpath={.items[*].metadata.name}", []runtime.Object{web, db}, "web db", false},
+		{"jsonpath missing key", "jsonpath={.metadata.uid}", []runtime.Object{web}, "", false},
+		{"go-template", "go-template={{.metadata.name}}/{{.metadata.namespace}}", []runtime.Object{web}, "web/apps", false},
+		{"custom-columns", "custom-columns=NAME:.metadata.name,APP:metadata.labels.app,CONTAINERS:{.spec.containers[*].name}", []runtime.Object{web, db},
+			"NAME   APP      CONTAINERS\nweb    web      nginx,proxy\ndb     <none>   <none>\n", false},
+		{"json typed", "json", []runtime.Object{typed}, `"kind": "ConfigMap"`, false},
+		{"yaml list", "yaml", []runtime.Object{web, typed}, "kind: List", false},
+		{"invalid format", "xml", []runtime.Object{web}, "", true},
+		{"missing template", "jsonpath", []runtime.Object{web}, "", true},
+		{"invalid custom-columns", "custom-columns=NAME", []runtime.Object{web}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := PrintObjects(&out, tt.output, tt.objs...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PrintObjects() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			got := out.String()
+			if tt.output == "json" || tt.output == "yaml" {
+				if !strings.Contains(got, tt.want) {
+					t.Errorf("PrintObjects() = %q, want to contain %q", got, tt.want)
+				}
+				return
+			}
+			if got != tt.want {
+				t.Errorf("PrintObjects() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewCustomColumnsPrinterFromTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		want     []string
+		wantErr  bool
+	}{
+		{"valid", "NAME   KIND\n.metadata.name   .kind\n", []string{"NAME", "KIND"}, false},
+		{"blank lines", "\nNAME\n\n{.metadata.name}\n", []string{"NAME"}, false},
+		{"single line", "NAME KIND\n", nil, true},
+		{"mismatched columns", "NAME KIND\n.metadata.name\n", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := newCustomColumnsPrinterFromTemplate(tt.template)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newCustomColumnsPrinterFromTemplate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			var got []string
+			for _, c := range p.columns {
+				got = append(got, c.header)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("newCustomColumnsPrinterFromTemplate() headers = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_Print_table(t *testing.T) {
+	c := testServerClient(t, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !strings.Contains(req.Header.Get("Accept"), "as=Table") {
+			http.Error(w, "expected a table", http.StatusNotAcceptable)
+			return
+		}
+		name := req.URL.Path[strings.LastIndex(req.URL.Path, "/")+1:]
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"apiVersion": "meta.k8s.io/v1", "kind": "Table",
+			"columnDefinitions": [{"name": "Name", "type": "string"}, {"name": "Ready", "type": "string"}, {"name": "Node", "type": "string", "priority": 1}],
+			"rows": [{"cells": ["` + name + `", "1/1", "node-1"]}]}`))
+	}))
+
+	web := testInfo(t, `{"apiVersion": "v1", "kind": "Pod", "metadata": {"name": "web", "namespace": "test"}}`)
+	db := testInfo(t, `{"apiVersion": "v1", "kind": "Pod", "metadata": {"name": "db", "namespace": "test"}}`)
+	for _, info := range []*resource.Info{web, db} {
+		info.Mapping.Resource.Resource = "pods"
+	}
+
+	tests := []struct {
+		name   string
+		output string
+		want   string
+	}{
+		{"table", "", "NAME   READY\nweb    1/1\ndb     1/1\n"},
+		{"wide", "wide", "NAME   READY   NODE\nweb    1/1     node-1\ndb     1/1     node-1\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := c.Print(&out, tt.output, web, db); err != nil {
+				t.Fatalf("Client.Print() error = %v", err)
+			}
+			if got := out.String(); got != tt.want {
+				t.Errorf("Client.Print() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
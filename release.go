@@ -0,0 +1,459 @@
+package klient
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+const (
+	// releaseLabel is the label with the release name of the inventory objects
+	releaseLabel = "klient.johandry.com/release"
+	// managedByLabel identifies the inventory objects created by klient
+	managedByLabel = "app.kubernetes.io/managed-by"
+	managedBy      = "klient"
+	// releaseKey is the key of the inventory data with the release
+	releaseKey = "release"
+	// releasePrefix is the prefix of the inventory objects name
+	releasePrefix = "klient-release-"
+)
+
+// ReleaseStorage is the Kind of the objects where the release inventory is stored
+type ReleaseStorage string
+
+const (
+	// ReleaseStorageConfigMap stores the inventory in ConfigMaps. It's the default
+	ReleaseStorageConfigMap ReleaseStorage = "ConfigMap"
+	// ReleaseStorageSecret stores the inventory in Secrets
+	ReleaseStorageSecret ReleaseStorage = "Secret"
+)
+
+// Release is a named set of objects applied together and tracked in an
+// inventory in the cluster
+type Release struct {
+	Name      string            `json:"name"`
+	Namespace string            `json:"namespace"`
+	Revision  int               `json:"revision"`
+	Digest    string            `json:"digest"`
+	Objects   []ObjectReference `json:"objects"`
+	UpdatedAt time.Time         `json:"updatedAt"`
+}
+
+// ObjectReference identifies an object owned by a release
+type ObjectReference struct {
+	Group     string    `json:"group,omitempty"`
+	Version   string    `json:"version"`
+	Kind      string    `json:"kind"`
+	Namespace string    `json:"namespace,omitempty"`
+	Name      string    `json:"name"`
+	UID       types.UID `json:"uid,omitempty"`
+}
+
+// GroupVersionKind returns the GroupVersionKind of the referenced object
+func (o ObjectReference) GroupVersionKind() schema.GroupVersionKind {
+	return schema.GroupVersionKind{Group: o.Group, Version: o.Version, Kind: o.Kind}
+}
+
+func (o ObjectReference) String() string {
+	if o.Namespace == "" {
+		return fmt.Sprintf("%s %s", o.Kind, o.Name)
+	}
+	return fmt.Sprintf("%s %s/%s", o.Kind, o.Namespace, o.Name)
+}
+
+// ApplyRelease applies the given content and records the applied objects in
+// the inventory of the release with the given name
+func (c *Client) ApplyRelease(name string, content []byte) (*Release, error) {
+	r := c.ResultForContent(content, nil)
+	return c.ApplyReleaseResource(name, r)
+}
+
+// ApplyReleaseFiles applies the resource(s) from the given filenames (file,
+// directory, STDIN, HTTP URLs or remote sources) and records the applied
+// objects in the inventory of the release with the given name
+func (c *Client) ApplyReleaseFiles(name string, filenames ...string) (*Release, error) {
+	r := c.ResultForFilenameParam(filenames, nil)
+	return c.ApplyReleaseResource(name, r)
+}
+
+// ApplyReleaseResource applies the given resource and, if it succeed, records
+// the applied objects and the digest of their manifests in the inventory of
+// the release. The objects owned by the release and not in the resource are
+// not deleted, they are kept in the inventory so they are deleted with the
+// release. If some objects fail to be applied, the objects applied are
+// recorded anyway and returned in the release with the error, so they can be
+// deleted with the release. Create the resources with `ResultForFilenameParam`
+// or `ResultForContent`
+func (c *Client) ApplyReleaseResource(name string, r *resource.Result) (*Release, error) {
+	return c.applyRelease(name, r, nil)
+}
+
+// applyRelease applies the given resource and records it in the inventory of
+// the release. The owned objects not in the resource are passed to prune, if
+// it is not nil and succeed they are not recorded anymore, otherwise they are
+// kept in the inventory
+func (c *Client) applyRelease(name string, r *resource.Result, prune func([]ObjectReference) error) (*Release, error) {
+	if err := validateReleaseName(name); err != nil {
+		return nil, err
+	}
+	if err := r.Err(); err != nil {
+		return nil, err
+	}
+
+	var digest string
+	var manifests []byte
+	var applied []*resource.Info
+	setDigest := func(infos []*resource.Info) (err error) {
+		if digest, err = manifestsDigest(infos); err != nil {
+			return err
+		}
+		manifests, err = encodeManifests(infos)
+		return err
+	}
+	applyFn := c.applier()
+	err := c.visit(r, func(info *resource.Info, err error) error {
+		if err := applyFn(info, err); err != nil {
+			return err
+		}
+		applied = append(applied, info)
+		return nil
+	}, c.enforcePolicy, c.createNamespace, setDigest)
+	if err != nil && len(applied) == 0 {
+		return nil, err
+	}
+	if err != nil {
+		return c.savePartialRelease(name, applied, err)
+	}
+
+	release := &Release{
+		Name:      name,
+		Namespace: c.releaseNamespace(),
+		Digest:    digest,
+		Objects:   objectReferences(applied),
+		UpdatedAt: time.Now().UTC(),
+	}
+	current, _, err := c.loadRelease(name)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, fmt.Errorf("cannot record the release %q. %s", name, err)
+	}
+	if current != nil {
+		stale := pruneCandidates(current.Objects, release.Objects)
+		if prune == nil || prune(stale) != nil {
+			// The objects owned and not applied this time are still owned
+			release.Objects = append(stale, release.Objects...)
+		}
+	}
+	if c.DryRun {
+		return release, nil
+	}
+	if err := c.saveRelease(release); err != nil {
+		return nil, fmt.Errorf("cannot record the release %q. %s", name, err)
+	}
+	if err := c.saveRevision(release, manifests); err != nil {
+		return release, fmt.Errorf("cannot record the revision %d of the release %q. %s", release.Revision, name, err)
+	}
+
+	return release, nil
+}
+
+// savePartialRelease records in the inventory of the release the objects
+// applied before the apply error, keeping the objects it already owns. The
+// release has no digest and no revision is stored for it, the manifests were
+// not completely applied
+func (c *Client) savePartialRelease(name string, applied []*resource.Info, applyErr error) (*Release, error) {
+	release := &Release{
+		Name:      name,
+		Namespace: c.releaseNamespace(),
+		Objects:   objectReferences(applied),
+		UpdatedAt: time.Now().UTC(),
+	}
+	if c.DryRun {
+		return release, applyErr
+	}
+
+	current, _, err := c.loadRelease(name)
+	if err != nil && !errors.IsNotFound(err) {
+		return release, fmt.Errorf("cannot record the objects applied to the release %q. %s. %s", name, err, applyErr)
+	}
+	if current != nil {
+		// The objects owned and not applied this time are still owned
+		release.Objects = append(pruneCandidates(current.Objects, release.Objects), release.Objects...)
+	}
+	if err := c.saveRelease(release); err != nil {
+		return release, fmt.Errorf("cannot record the objects applied to the release %q. %s. %s", name, err, applyErr)
+	}
+	return release, applyErr
+}
+
+// GetRelease returns the release with the given name, with the objects it owns
+func (c *Client) GetRelease(name string) (*Release, error) {
+	release, _, err := c.loadRelease(name)
+	return release, err
+}
+
+// ListReleases returns all the releases in the inventory, sorted by name
+func (c *Client) ListReleases() ([]*Release, error) {
+	selector := metav1.ListOptions{LabelSelector: managedByLabel + "=" + managedBy + "," + releaseLabel}
+	namespace := c.releaseNamespace()
+
+	var data []string
+	switch c.ReleaseStorage {
+	case ReleaseStorageSecret:
+		list, err := c.Clientset.CoreV1().Secrets(namespace).List(selector)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range list.Items {
+			if s.Name == releasePrefix+s.Labels[releaseLabel] {
+				data = append(data, string(s.Data[releaseKey]))
+			}
+		}
+	default:
+		list, err := c.Clientset.CoreV1().ConfigMaps(namespace).List(selector)
+		if err != nil {
+			return nil, err
+		}
+		for _, cm := range list.Items {
+			if cm.Name == releasePrefix+cm.Labels[releaseLabel] {
+				data = append(data, cm.Data[releaseKey])
+			}
+		}
+	}
+
+	releases := make([]*Release, 0, len(data))
+	for _, d := range data {
+		var release Release
+		if err := json.Unmarshal([]byte(d), &release); err != nil {
+			return nil, fmt.Errorf("cannot decode a release inventory. %s", err)
+		}
+		releases = append(releases, &release)
+	}
+	sort.Slice(releases, func(i, j int) bool { return releases[i].Name < releases[j].Name })
+
+	return releases, nil
+}
+
+// DeleteRelease deletes all the objects owned by the release with the given
+// name and then the release inventory and history. In dry-run, the objects
+// deletion is server-side dry-run and the inventory and history are kept
+func (c *Client) DeleteRelease(name string) error {
+	release, _, err := c.loadRelease(name)
+	if err != nil {
+		return err
+	}
+
+	if err := c.deleteObjects(release.Objects); err != nil {
+		return err
+	}
+	if c.DryRun {
+		return nil
+	}
+
+	if err := c.deleteHistory(name); err != nil {
+		return err
+	}
+	return c.deleteInventory(releasePrefix + name)
+}
+
+// deleteObjects deletes the referenced objects, the objects not found are
+// ignored. The deletions are server-side dry-run if the client is in dry-run
+func (c *Client) deleteObjects(objects []ObjectReference) error {
+	policy := metav1.DeletePropagationBackground
+	options := &metav1.DeleteOptions{PropagationPolicy: &policy}
+	if c.DryRun {
+		options.DryRun = []string{metav1.DryRunAll}
+	}
+	errs := []error{}
+	// Delete in reverse order to delete the dependent objects first
+	for i := len(objects) - 1; i >= 0; i-- {
+		obj := objects[i]
+		ri, _, err := c.resourceInterface(obj.GroupVersionKind(), obj.Namespace)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("cannot delete %s. %s", obj, err))
+			continue
+		}
+		if err := ri.Delete(obj.Name, options); err != nil && !errors.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("cannot delete %s. %s", obj, err))
+		}
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// releaseNamespace is the namespace of the release inventory
+func (c *Client) releaseNamespace() string {
+	if c.ReleaseNamespace != "" {
+		return c.ReleaseNamespace
+	}
+	return c.namespace
+}
+
+// manifestsDigest returns the digest of the manifests of the given resources
+func manifestsDigest(infos []*resource.Info) (string, error) {
+	h := sha256.New()
+	for _, info := range infos {
+		data, err := runtime.Encode(unstructured.UnstructuredJSONScheme, info.Object)
+		if err != nil {
+			return "", failedTo("encode", info, err)
+		}
+		h.Write(data)
+		h.Write([]byte("\n"))
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func objectReferences(infos []*resource.Info) []ObjectReference {
+	refs := make([]ObjectReference, 0, len(infos))
+	for _, info := range infos {
+		ref := ObjectReference{
+			Namespace: info.Namespace,
+			Name:      info.Name,
+		}
+		if info.Mapping != nil {
+			gvk := info.Mapping.GroupVersionKind
+			ref.Group, ref.Version, ref.Kind = gvk.Group, gvk.Version, gvk.Kind
+		}
+		if obj, err := meta.Accessor(info.Object); err == nil {
+			ref.UID = obj.GetUID()
+		}
+		refs = append(refs, ref)
+	}
+	return refs
+}
+
+// saveRelease creates or updates the inventory of the release, increasing the
+// revision
+func (c *Client) saveRelease(release *Release) error {
+	current, obj, err := c.loadRelease(release.Name)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	release.Revision = 1
+	if current != nil {
+		release.Revision = current.Revision + 1
+	}
+
+	data, err := json.Marshal(release)
+	if err != nil {
+		return err
+	}
+	return c.saveInventory(releasePrefix+release.Name, release.Name, map[string][]byte{releaseKey: data}, obj)
+}
+
+// validateReleaseName fails if the release name is not a DNS label. The name
+// is a label value and it cannot have dots, so the inventory name of a release
+// is never the name of a revision of another release
+func validateReleaseName(name string) error {
+	if errs := validation.IsDNS1123Label(name); len(errs) != 0 {
+		return fmt.Errorf("invalid release name %q. %s", name, strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// loadRelease returns the release with the given name and its inventory object
+func (c *Client) loadRelease(name string) (*Release, metav1.Object, error) {
+	if err := validateReleaseName(name); err != nil {
+		return nil, nil, err
+	}
+	data, obj, err := c.loadInventory(releasePrefix + name)
+	if err != nil {
+		return nil, nil, err
+	}
+	var release Release
+	if err := json.Unmarshal(data[releaseKey], &release); err != nil {
+		return nil, nil, fmt.Errorf("cannot decode the release %q. %s", name, err)
+	}
+	return &release, obj, nil
+}
+
+// loadInventory returns the data of the inventory object with the given name
+func (c *Client) loadInventory(name string) (map[string][]byte, metav1.Object, error) {
+	namespace := c.releaseNamespace()
+	if c.ReleaseStorage == ReleaseStorageSecret {
+		secret, err := c.Clientset.CoreV1().Secrets(namespace).Get(name, metav1.GetOptions{})
+		if err != nil {
+			return nil, nil, err
+		}
+		return secret.Data, secret, nil
+	}
+
+	cm, err := c.Clientset.CoreV1().ConfigMaps(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	data := make(map[string][]byte, len(cm.Data))
+	for k, v := range cm.Data {
+		data[k] = []byte(v)
+	}
+	return data, cm, nil
+}
+
+// saveInventory creates the inventory object with the given data or, if the
+// current object is given, updates it
+func (c *Client) saveInventory(name, release string, data map[string][]byte, current metav1.Object) error {
+	namespace := c.releaseNamespace()
+	create := current == nil
+	objMeta := metav1.ObjectMeta{
+		Name:      name,
+		Namespace: namespace,
+		Labels: map[string]string{
+			managedByLabel: managedBy,
+			releaseLabel:   release,
+		},
+	}
+	if !create {
+		objMeta.ResourceVersion = current.GetResourceVersion()
+	}
+
+	if c.ReleaseStorage == ReleaseStorageSecret {
+		secret := &corev1.Secret{ObjectMeta: objMeta, Type: corev1.SecretTypeOpaque, Data: data}
+		if create {
+			_, err := c.Clientset.CoreV1().Secrets(namespace).Create(secret)
+			return err
+		}
+		_, err := c.Clientset.CoreV1().Secrets(namespace).Update(secret)
+		return err
+	}
+
+	cm := &corev1.ConfigMap{ObjectMeta: objMeta, Data: make(map[string]string, len(data))}
+	for k, v := range data {
+		cm.Data[k] = string(v)
+	}
+	if create {
+		_, err := c.Clientset.CoreV1().ConfigMaps(namespace).Create(cm)
+		return err
+	}
+	_, err := c.Clientset.CoreV1().ConfigMaps(namespace).Update(cm)
+	return err
+}
+
+// deleteInventory deletes the inventory object with the given name
+func (c *Client) deleteInventory(name string) error {
+	namespace := c.releaseNamespace()
+	var err error
+	if c.ReleaseStorage == ReleaseStorageSecret {
+		err = c.Clientset.CoreV1().Secrets(namespace).Delete(name, &metav1.DeleteOptions{})
+	} else {
+		err = c.Clientset.CoreV1().ConfigMaps(namespace).Delete(name, &metav1.DeleteOptions{})
+	}
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,424 @@
+package klient
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+)
+
+// storeHandler is a fake API server storing the ConfigMaps and Secrets of the
+// namespace `test`. The objects named `fail` cannot be created and the dry-run
+// requests are not persisted. It returns the function to get the names, i.e.
+// `configmaps/web`, of the stored objects
+func storeHandler(t *testing.T) (http.HandlerFunc, func() []string) {
+	var mu sync.Mutex
+	objects := map[string]map[string]interface{}{}
+	version := 0
+	kinds := map[string]interface{}{"configmaps": corev1.ConfigMap{}, "secrets": corev1.Secret{}}
+	listKinds := map[string]string{"configmaps": "ConfigMapList", "secrets": "SecretList"}
+
+	writeStatus := func(w http.ResponseWriter, err *errors.StatusError) {
+		w.WriteHeader(int(err.Status().Code))
+		status := err.Status()
+		status.Kind, status.APIVersion = "Status", "v1"
+		json.NewEncoder(w).Encode(status)
+	}
+	store := func(key string, obj map[string]interface{}) {
+		version++
+		u := &unstructured.Unstructured{Object: obj}
+		if u.GetUID() == "" {
+			u.SetUID(types.UID(fmt.Sprintf("uid-%d", version)))
+		}
+		u.SetNamespace("test")
+		u.SetResourceVersion(fmt.Sprint(version))
+		objects[key] = obj
+	}
+
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+
+		switch req.URL.Path {
+		case "/api":
+			json.NewEncoder(w).Encode(metav1.APIVersions{Versions: []string{"v1"}})
+			return
+		case "/apis":
+			json.NewEncoder(w).Encode(metav1.APIGroupList{})
+			return
+		case "/api/v1":
+			json.NewEncoder(w).Encode(metav1.APIResourceList{GroupVersion: "v1", APIResources: []metav1.APIResource{
+				{Name: "configmaps", Kind: "ConfigMap", Namespaced: true},
+				{Name: "secrets", Kind: "Secret", Namespaced: true},
+			}})
+			return
+		}
+
+		// The dry-run is a query parameter, or in the options of a delete
+		body, _ := ioutil.ReadAll(req.Body)
+		options := metav1.DeleteOptions{}
+		if req.Method == http.MethodDelete {
+			json.Unmarshal(body, &options)
+		}
+		dryRun := req.URL.Query().Get("dryRun") == metav1.DryRunAll || len(options.DryRun) != 0
+		persist := func(key string, obj map[string]interface{}) {
+			if !dryRun {
+				store(key, obj)
+			}
+		}
+
+		parts := strings.Split(strings.TrimPrefix(req.URL.Path, "/api/v1/namespaces/test/"), "/")
+		dataStruct, ok := kinds[parts[0]]
+		if !ok || len(parts) > 2 {
+			http.NotFound(w, req)
+			return
+		}
+		gr := schema.GroupResource{Resource: parts[0]}
+
+		// Collection requests
+		if len(parts) == 1 {
+			switch req.Method {
+			case http.MethodGet:
+				selector, err := labels.Parse(req.URL.Query().Get("labelSelector"))
+				if err != nil {
+					writeStatus(w, errors.NewBadRequest(err.Error()))
+					return
+				}
+				list := &unstructured.UnstructuredList{Object: map[string]interface{}{"apiVersion": "v1", "kind": listKinds[parts[0]]}}
+				for key, obj := range objects {
+					u := unstructured.Unstructured{Object: obj}
+					if strings.HasPrefix(key, parts[0]+"/") && selector.Matches(labels.Set(u.GetLabels())) {
+						list.Items = append(list.Items, *u.DeepCopy())
+					}
+				}
+				data, _ := list.MarshalJSON()
+				w.Write(data)
+			case http.MethodPost:
+				var obj map[string]interface{}
+				json.Unmarshal(body, &obj)
+				u := &unstructured.Unstructured{Object: obj}
+				key := parts[0] + "/" + u.GetName()
+				switch {
+				case u.GetName() == "fail":
+					writeStatus(w, errors.NewForbidden(gr, u.GetName(), fmt.Errorf("the test server rejects it")))
+					return
+				case objects[key] != nil:
+					writeStatus(w, errors.NewAlreadyExists(gr, u.GetName()))
+					return
+				}
+				persist(key, obj)
+				w.WriteHeader(http.StatusCreated)
+				json.NewEncoder(w).Encode(obj)
+			default:
+				http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			}
+			return
+		}
+
+		// Object requests
+		key := parts[0] + "/" + parts[1]
+		current := objects[key]
+		if current == nil {
+			writeStatus(w, errors.NewNotFound(gr, parts[1]))
+			return
+		}
+		switch req.Method {
+		case http.MethodGet:
+			json.NewEncoder(w).Encode(current)
+		case http.MethodPut:
+			var obj map[string]interface{}
+			json.Unmarshal(body, &obj)
+			obj["metadata"].(map[string]interface{})["uid"] = current["metadata"].(map[string]interface{})["uid"]
+			persist(key, obj)
+			json.NewEncoder(w).Encode(obj)
+		case http.MethodPatch:
+			original, _ := json.Marshal(current)
+			patched, err := strategicpatch.StrategicMergePatch(original, body, dataStruct)
+			if err != nil {
+				writeStatus(w, errors.NewBadRequest(err.Error()))
+				return
+			}
+			var obj map[string]interface{}
+			json.Unmarshal(patched, &obj)
+			persist(key, obj)
+			json.NewEncoder(w).Encode(obj)
+		case http.MethodDelete:
+			if !dryRun {
+				objects[key] = nil
+			}
+			json.NewEncoder(w).Encode(metav1.Status{TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"}, Status: metav1.StatusSuccess})
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	}
+
+	return handler, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		names := []string{}
+		for key, obj := range objects {
+			if obj != nil {
+				names = append(names, key)
+			}
+		}
+		sort.Strings(names)
+		return names
+	}
+}
+
+// testConfigMaps returns the manifests of ConfigMaps with the given names
+func testConfigMaps(names ...string) []byte {
+	docs := make([]string, 0, len(names))
+	for _, name := range names {
+		docs = append(docs, fmt.Sprintf("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: %s\ndata:\n  key: %s\n", name, name))
+	}
+	return []byte(strings.Join(docs, "---\n"))
+}
+
+// objectNames returns the names of the referenced objects
+func objectNames(objects []ObjectReference) []string {
+	names := make([]string, 0, len(objects))
+	for _, obj := range objects {
+		names = append(names, obj.Name)
+	}
+	return names
+}
+
+func TestClient_ApplyRelease(t *testing.T) {
+	tests := []struct {
+		name        string
+		storage     ReleaseStorage
+		inventory   string
+		firstApply  []string
+		secondApply []string
+		wantObjects []string
+		wantErr     bool
+	}{
+		{"configmap storage", ReleaseStorageConfigMap, "configmaps/klient-release-app", []string{"web"}, []string{"web", "db"}, []string{"web", "db"}, false},
+		{"secret storage", ReleaseStorageSecret, "secrets/klient-release-app", []string{"web"}, []string{"web", "db"}, []string{"web", "db"}, false},
+		{"object removed", ReleaseStorageConfigMap, "configmaps/klient-release-app", []string{"web", "db"}, []string{"web"}, []string{"db", "web"}, false},
+		{"partial failure", ReleaseStorageConfigMap, "configmaps/klient-release-app", []string{"web", "db"}, []string{"fail", "web", "cache"}, []string{"db", "web", "cache"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, stored := storeHandler(t)
+			c := testServerClient(t, handler)
+			c.ReleaseStorage = tt.storage
+
+			first, err := c.ApplyRelease("app", testConfigMaps(tt.firstApply...))
+			if err != nil {
+				t.Fatalf("Client.ApplyRelease() error = %v", err)
+			}
+			if first.Revision != 1 || first.Digest == "" || first.Namespace != "test" {
+				t.Errorf("Client.ApplyRelease() = %+v, want revision 1 with digest in namespace test", first)
+			}
+
+			second, err := c.ApplyRelease("app", testConfigMaps(tt.secondApply...))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Client.ApplyRelease() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if second == nil {
+				t.Fatalf("Client.ApplyRelease() returned no release")
+			}
+
+			got, err := c.GetRelease("app")
+			if err != nil {
+				t.Fatalf("Client.GetRelease() error = %v", err)
+			}
+			if got.Revision != 2 {
+				t.Errorf("Client.GetRelease() revision = %d, want 2", got.Revision)
+			}
+			if names := objectNames(got.Objects); strings.Join(names, ",") != strings.Join(tt.wantObjects, ",") {
+				t.Errorf("Client.GetRelease() objects = %v, want %v", names, tt.wantObjects)
+			}
+			for _, obj := range got.Objects {
+				if obj.Kind != "ConfigMap" || obj.Version != "v1" || obj.Namespace != "test" || obj.UID == "" {
+					t.Errorf("Client.GetRelease() object = %+v, want a ConfigMap in test with UID", obj)
+				}
+			}
+			if tt.wantErr && got.Digest != "" {
+				t.Errorf("Client.GetRelease() digest = %q, want no digest for a partial apply", got.Digest)
+			}
+
+			found := false
+			for _, name := range stored() {
+				found = found || name == tt.inventory
+			}
+			if !found {
+				t.Errorf("the inventory %q is not stored, stored objects = %v", tt.inventory, stored())
+			}
+		})
+	}
+}
+
+func TestClient_ApplyRelease_failure(t *testing.T) {
+	handler, stored := storeHandler(t)
+	c := testServerClient(t, handler)
+
+	release, err := c.ApplyRelease("app", testConfigMaps("fail"))
+	if err == nil || release != nil {
+		t.Fatalf("Client.ApplyRelease() = %v, %v, want an error and no release", release, err)
+	}
+	if got := stored(); len(got) != 0 {
+		t.Errorf("Client.ApplyRelease() stored %v, want nothing", got)
+	}
+}
+
+func TestClient_ApplyRelease_removedObject(t *testing.T) {
+	handler, stored := storeHandler(t)
+	c := testServerClient(t, handler)
+
+	if _, err := c.ApplyRelease("app", testConfigMaps("web", "db")); err != nil {
+		t.Fatalf("Client.ApplyRelease() error = %v", err)
+	}
+	release, err := c.ApplyRelease("app", testConfigMaps("web"))
+	if err != nil {
+		t.Fatalf("Client.ApplyRelease() error = %v", err)
+	}
+	if names := objectNames(release.Objects); strings.Join(names, ",") != "db,web" {
+		t.Errorf("Client.ApplyRelease() objects = %v, want [db web]", names)
+	}
+
+	// The object removed from the manifests is not deleted by the apply but
+	// it is still owned, so it is deleted with the release
+	want := []string{"configmaps/db", "configmaps/klient-release-app", "configmaps/web", "secrets/klient-release-app.v1", "secrets/klient-release-app.v2"}
+	if got := stored(); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Client.ApplyRelease() stored %v, want %v", got, want)
+	}
+	if err := c.DeleteRelease("app"); err != nil {
+		t.Fatalf("Client.DeleteRelease() error = %v", err)
+	}
+	if got := stored(); len(got) != 0 {
+		t.Errorf("Client.DeleteRelease() left %v, want nothing", got)
+	}
+}
+
+func TestClient_GetRelease(t *testing.T) {
+	handler, _ := storeHandler(t)
+	c := testServerClient(t, handler)
+
+	if _, err := c.GetRelease("app"); !errors.IsNotFound(err) {
+		t.Errorf("Client.GetRelease() error = %v, want not found", err)
+	}
+	if _, err := c.ApplyRelease("app", testConfigMaps("web")); err != nil {
+		t.Fatalf("Client.ApplyRelease() error = %v", err)
+	}
+	got, err := c.GetRelease("app")
+	if err != nil {
+		t.Fatalf("Client.GetRelease() error = %v", err)
+	}
+	if got.Name != "app" || got.Revision != 1 || len(got.Objects) != 1 {
+		t.Errorf("Client.GetRelease() = %+v, want the revision 1 of app with 1 object", got)
+	}
+}
+
+func TestClient_ListReleases(t *testing.T) {
+	for _, storage := range []ReleaseStorage{ReleaseStorageConfigMap, ReleaseStorageSecret} {
+		t.Run(string(storage), func(t *testing.T) {
+			handler, _ := storeHandler(t)
+			c := testServerClient(t, handler)
+			c.ReleaseStorage = storage
+
+			for _, name := range []string{"web", "db"} {
+				if _, err := c.ApplyRelease(name, testConfigMaps(name)); err != nil {
+					t.Fatalf("Client.ApplyRelease() error = %v", err)
+				}
+			}
+			// The history is not listed as releases
+			if _, err := c.ApplyRelease("web", testConfigMaps("web")); err != nil {
+				t.Fatalf("Client.ApplyRelease() error = %v", err)
+			}
+
+			releases, err := c.ListReleases()
+			if err != nil {
+				t.Fatalf("Client.ListReleases() error = %v", err)
+			}
+			got := []string{}
+			for _, r := range releases {
+				got = append(got, fmt.Sprintf("%s.%d", r.Name, r.Revision))
+			}
+			if want := "db.1,web.2"; strings.Join(got, ",") != want {
+				t.Errorf("Client.ListReleases() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestClient_DeleteRelease(t *testing.T) {
+	handler, stored := storeHandler(t)
+	c := testServerClient(t, handler)
+
+	if _, err := c.ApplyRelease("app", testConfigMaps("web", "db")); err != nil {
+		t.Fatalf("Client.ApplyRelease() error = %v", err)
+	}
+	if _, err := c.ApplyRelease("other", testConfigMaps("cache")); err != nil {
+		t.Fatalf("Client.ApplyRelease() error = %v", err)
+	}
+	if err := c.DeleteRelease("app"); err != nil {
+		t.Fatalf("Client.DeleteRelease() error = %v", err)
+	}
+
+	want := []string{"configmaps/cache", "configmaps/klient-release-other", "secrets/klient-release-other.v1"}
+	if got := stored(); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Client.DeleteRelease() left %v, want %v", got, want)
+	}
+	if err := c.DeleteRelease("app"); !errors.IsNotFound(err) {
+		t.Errorf("Client.DeleteRelease() error = %v, want not found", err)
+	}
+}
+
+func TestClient_DeleteRelease_dryRun(t *testing.T) {
+	store, stored := storeHandler(t)
+	var mu sync.Mutex
+	deletes := []string{}
+	c := testServerClient(t, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method == http.MethodDelete {
+			body, _ := ioutil.ReadAll(req.Body)
+			options := metav1.DeleteOptions{}
+			json.Unmarshal(body, &options)
+			mu.Lock()
+			deletes = append(deletes, fmt.Sprintf("%s dryRun=%s", req.URL.Path, strings.Join(options.DryRun, ",")))
+			mu.Unlock()
+			req.Body = ioutil.NopCloser(strings.NewReader(string(body)))
+		}
+		store(w, req)
+	}))
+
+	if _, err := c.ApplyRelease("app", testConfigMaps("web", "db")); err != nil {
+		t.Fatalf("Client.ApplyRelease() error = %v", err)
+	}
+	want := stored()
+
+	c.DryRun = true
+	if err := c.DeleteRelease("app"); err != nil {
+		t.Fatalf("Client.DeleteRelease() error = %v", err)
+	}
+
+	if got := stored(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Client.DeleteRelease() in dry-run left %v, want %v", got, want)
+	}
+	wantDeletes := []string{
+		"/api/v1/namespaces/test/configmaps/db dryRun=All",
+		"/api/v1/namespaces/test/configmaps/web dryRun=All",
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if !reflect.DeepEqual(deletes, wantDeletes) {
+		t.Errorf("Client.DeleteRelease() in dry-run deletes = %q, want %q", deletes, wantDeletes)
+	}
+}
@@ -21,7 +21,7 @@ func (c *Client) ReplaceResource(r *resource.Result) error {
 	if err := r.Err(); err != nil {
 		return err
 	}
-	return r.Visit(replace)
+	return c.visit(r, replace, c.enforcePolicy)
 }
 
 func replace(info *resource.Info, err error) error {
@@ -0,0 +1,109 @@
+package klient
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/kubectl/pkg/polymorphichelpers"
+	"k8s.io/kubectl/pkg/scheme"
+)
+
+// RolloutStatus returns the rollout status of the Deployment, DaemonSet or
+// StatefulSet with the given name, and true if the rollout is complete
+func (c *Client) RolloutStatus(kind, name string) (string, bool, error) {
+	info, err := c.object(kind, name)
+	if err != nil {
+		return "", false, err
+	}
+	done, msg, err := rolloutComplete(info)
+	return msg, done, err
+}
+
+// RolloutRestart restarts the pods of the Deployment, DaemonSet or StatefulSet
+// with the given name, setting the `restartedAt` annotation of the pod template
+func (c *Client) RolloutRestart(kind, name string) error {
+	return c.rolloutPatch("restart", kind, name, polymorphichelpers.ObjectRestarterFn)
+}
+
+// RolloutPause pauses the rollout of the Deployment with the given name
+func (c *Client) RolloutPause(kind, name string) error {
+	return c.rolloutPatch("pause", kind, name, polymorphichelpers.ObjectPauserFn)
+}
+
+// RolloutResume resumes the paused rollout of the Deployment with the given name
+func (c *Client) RolloutResume(kind, name string) error {
+	return c.rolloutPatch("resume", kind, name, polymorphichelpers.ObjectResumerFn)
+}
+
+// RolloutUndo rolls back the Deployment, DaemonSet or StatefulSet with the
+// given name to the given revision, or to the previous one if the revision is
+// 0. Returns the result of the rollback
+func (c *Client) RolloutUndo(kind, name string, toRevision int64) (string, error) {
+	info, err := c.object(kind, name)
+	if err != nil {
+		return "", err
+	}
+	rollbacker, err := polymorphichelpers.RollbackerFn(c.factory, info.Mapping)
+	if err != nil {
+		return "", failedTo("rollback", info, err)
+	}
+	result, err := rollbacker.Rollback(info.Object, nil, toRevision, false)
+	if err != nil {
+		return "", failedTo("rollback", info, err)
+	}
+	return result, nil
+}
+
+// rolloutPatch patches the workload with the changes made to the object by
+// the given function, which returns the modified object encoded
+func (c *Client) rolloutPatch(action, kind, name string, mutateFn func(runtime.Object) ([]byte, error)) error {
+	info, err := c.object(kind, name)
+	if err != nil {
+		return err
+	}
+	original, err := runtime.Encode(scheme.DefaultJSONEncoder(), info.Object)
+	if err != nil {
+		return failedTo(action, info, err)
+	}
+	modified, err := mutateFn(info.Object)
+	if err != nil {
+		return failedTo(action, info, err)
+	}
+	patch, err := strategicpatch.CreateTwoWayMergePatch(original, modified, info.Object)
+	if err != nil {
+		return failedTo(action, info, err)
+	}
+	if string(patch) == "{}" {
+		return nil
+	}
+
+	obj, err := resource.NewHelper(info.Client, info.Mapping).Patch(info.Namespace, info.Name, types.StrategicMergePatchType, patch, nil)
+	if err != nil {
+		return failedTo(action, info, err)
+	}
+	return info.Refresh(obj, true)
+}
+
+// object returns the object of the given kind, or resource, and name in the
+// client namespace, resolved with the REST mapper
+func (c *Client) object(kind, name string) (*resource.Info, error) {
+	infos, err := c.factory.NewBuilder().
+		WithScheme(scheme.Scheme, scheme.Scheme.PrioritizedVersionsAllGroups()...).
+		NamespaceParam(c.namespace).DefaultNamespace().
+		ResourceTypeOrNameArgs(true, kind, name).
+		SingleResourceType().
+		Latest().
+		Flatten().
+		Do().
+		Infos()
+	if err != nil {
+		return nil, err
+	}
+	if len(infos) != 1 {
+		return nil, fmt.Errorf("expected one %s named %q, found %d", kind, name, len(infos))
+	}
+	return infos[0], nil
+}
@@ -0,0 +1,59 @@
+package klient
+
+import (
+	"os"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestClient_Rollout(t *testing.T) {
+	envContext := os.Getenv(contextEnvVarName)
+	envKubeconfig := os.Getenv(kubeconfigEnvVarName)
+
+	c, err := NewE(envContext, envKubeconfig)
+	if err != nil {
+		t.Fatalf("failed to create the client with context %q and kubeconfig %q", envContext, envKubeconfig)
+	}
+
+	deployment := []byte(`{"apiVersion": "apps/v1", "kind": "Deployment", "metadata": { "name": "test-rollout-0" },
+		"spec": { "replicas": 1, "selector": { "matchLabels": { "app": "test-rollout-0" } },
+			"template": { "metadata": { "labels": { "app": "test-rollout-0" } },
+				"spec": { "containers": [ { "name": "nginx", "image": "nginx:1.17" } ] } } } }`)
+	if err := c.Apply(deployment); err != nil {
+		t.Fatalf("Client.Apply() error = %v", err)
+	}
+	defer c.Delete(deployment)
+
+	if _, _, err := c.RolloutStatus("deployment", "test-rollout-0"); err != nil {
+		t.Errorf("Client.RolloutStatus() error = %v", err)
+	}
+
+	if err := c.RolloutPause("deployment", "test-rollout-0"); err != nil {
+		t.Fatalf("Client.RolloutPause() error = %v", err)
+	}
+	d, err := c.Clientset.AppsV1().Deployments("default").Get("test-rollout-0", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get the deployment. error = %v", err)
+	}
+	if !d.Spec.Paused {
+		t.Errorf("Client.RolloutPause() did not pause the deployment")
+	}
+	if err := c.RolloutRestart("deployment", "test-rollout-0"); err == nil {
+		t.Errorf("Client.RolloutRestart() expected an error restarting a paused deployment")
+	}
+
+	if err := c.RolloutResume("deployment", "test-rollout-0"); err != nil {
+		t.Fatalf("Client.RolloutResume() error = %v", err)
+	}
+	if err := c.RolloutRestart("deployment", "test-rollout-0"); err != nil {
+		t.Fatalf("Client.RolloutRestart() error = %v", err)
+	}
+	d, err = c.Clientset.AppsV1().Deployments("default").Get("test-rollout-0", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get the deployment. error = %v", err)
+	}
+	if _, ok := d.Spec.Template.Annotations["kubectl.kubernetes.io/restartedAt"]; !ok {
+		t.Errorf("Client.RolloutRestart() did not set the restartedAt annotation")
+	}
+}
@@ -0,0 +1,131 @@
+package klient
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/util/retry"
+)
+
+// scaleSubresource is the name of the subresource to scale a resource
+const scaleSubresource = "scale"
+
+// ScaleOptions are the preconditions to scale a resource and the time to wait
+// for the replicas
+type ScaleOptions struct {
+	// CurrentReplicas, if set, is the number of replicas the resource must have
+	// to be scaled
+	CurrentReplicas *int32
+	// ResourceVersion, if set, is the version the resource must have to be scaled
+	ResourceVersion string
+	// Timeout, if not zero, is the time to wait until the resource has the
+	// requested replicas
+	Timeout time.Duration
+}
+
+// NewScaleOptions creates a default ScaleOptions, without preconditions and
+// without waiting for the replicas
+func NewScaleOptions() *ScaleOptions {
+	return &ScaleOptions{}
+}
+
+// ScalePreconditionError is returned when the resource to scale does not
+// meet the preconditions
+type ScalePreconditionError struct {
+	Precondition string
+	Expected     string
+	Actual       string
+}
+
+func (e ScalePreconditionError) Error() string {
+	return fmt.Sprintf("expected %s to be %s, was %s", e.Precondition, e.Expected, e.Actual)
+}
+
+// Scale sets the replicas of the resource of the given kind and name, in the
+// client namespace, using its scale subresource
+func (c *Client) Scale(kind, name string, replicas int32) error {
+	return c.ScaleWithOptions(kind, name, replicas, nil)
+}
+
+// ScaleWithOptions sets the replicas of the resource of the given kind and
+// name, in the client namespace, using its scale subresource. The resource is
+// scaled only if it meets the preconditions in the options, otherwise a
+// ScalePreconditionError is returned. If a timeout is given, waits until the
+// status has the requested replicas
+func (c *Client) ScaleWithOptions(kind, name string, replicas int32, opt *ScaleOptions) error {
+	if opt == nil {
+		opt = NewScaleOptions()
+	}
+
+	ri, err := c.scaleInterface(kind)
+	if err != nil {
+		return err
+	}
+
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		scale, err := ri.Get(name, metav1.GetOptions{}, scaleSubresource)
+		if err != nil {
+			return err
+		}
+		if err := checkScalePreconditions(scale, opt); err != nil {
+			return err
+		}
+		if err := unstructured.SetNestedField(scale.Object, int64(replicas), "spec", "replicas"); err != nil {
+			return err
+		}
+		_, err = ri.Update(scale, metav1.UpdateOptions{}, scaleSubresource)
+		return err
+	})
+	if _, ok := err.(ScalePreconditionError); ok {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("cannot scale %s %q. %s", kind, name, err)
+	}
+
+	if opt.Timeout == 0 {
+		return nil
+	}
+	err = wait.PollImmediate(readinessInterval, opt.Timeout, func() (bool, error) {
+		scale, err := ri.Get(name, metav1.GetOptions{}, scaleSubresource)
+		if err != nil {
+			return false, err
+		}
+		current, _, err := unstructured.NestedInt64(scale.Object, "status", "replicas")
+		return current == int64(replicas), err
+	})
+	if err == wait.ErrWaitTimeout {
+		return fmt.Errorf("timed out waiting for %s %q to have %d replicas", kind, name, replicas)
+	}
+	return err
+}
+
+// scaleInterface returns the dynamic client for the resource of the given kind
+// in the client namespace
+func (c *Client) scaleInterface(kind string) (dynamic.ResourceInterface, error) {
+	mapping, err := c.mappingFor(kind)
+	if err != nil {
+		return nil, err
+	}
+	ri, _, err := c.resourceInterface(mapping.GroupVersionKind, c.namespace)
+	return ri, err
+}
+
+// checkScalePreconditions returns an error if the scale does not meet the
+// preconditions of the options
+func checkScalePreconditions(scale *unstructured.Unstructured, opt *ScaleOptions) error {
+	if opt.CurrentReplicas != nil {
+		current, _, _ := unstructured.NestedInt64(scale.Object, "spec", "replicas")
+		if current != int64(*opt.CurrentReplicas) {
+			return ScalePreconditionError{"replicas", fmt.Sprint(*opt.CurrentReplicas), fmt.Sprint(current)}
+		}
+	}
+	if opt.ResourceVersion != "" && opt.ResourceVersion != scale.GetResourceVersion() {
+		return ScalePreconditionError{"resource version", opt.ResourceVersion, scale.GetResourceVersion()}
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package klient
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sync"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestCheckScalePreconditions(t *testing.T) {
+	scale := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "autoscaling/v1",
+		"kind":       "Scale",
+		"metadata":   map[string]interface{}{"name": "web", "resourceVersion": "42"},
+		"spec":       map[string]interface{}{"replicas": int64(3)},
+	}}
+
+	zero, two, three := int32(0), int32(2), int32(3)
+	tests := []struct {
+		name    string
+		opt     *ScaleOptions
+		wantErr bool
+	}{
+		{"no preconditions", NewScaleOptions(), false},
+		{"replicas match", &ScaleOptions{CurrentReplicas: &three}, false},
+		{"replicas mismatch", &ScaleOptions{CurrentReplicas: &two}, true},
+		{"zero replicas mismatch", &ScaleOptions{CurrentReplicas: &zero}, true},
+		{"resource version match", &ScaleOptions{ResourceVersion: "42"}, false},
+		{"resource version mismatch", &ScaleOptions{ResourceVersion: "41"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkScalePreconditions(scale, tt.opt)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkScalePreconditions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if _, ok := err.(ScalePreconditionError); err != nil && !ok {
+				t.Errorf("checkScalePreconditions() error = %v, want a ScalePreconditionError", err)
+			}
+		})
+	}
+}
+
+// scaleHandler is a fake API server with the scale subresource of the web
+// deployment, with 3 replicas
+func scaleHandler(t *testing.T) (http.HandlerFunc, func() int64) {
+	var mu sync.Mutex
+	replicas := int64(3)
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+
+		switch req.URL.Path {
+		case "/api":
+			json.NewEncoder(w).Encode(metav1.APIVersions{Versions: []string{"v1"}})
+		case "/apis":
+			json.NewEncoder(w).Encode(metav1.APIGroupList{Groups: []metav1.APIGroup{{
+				Name:             "apps",
+				Versions:         []metav1.GroupVersionForDiscovery{{GroupVersion: "apps/v1", Version: "v1"}},
+				PreferredVersion: metav1.GroupVersionForDiscovery{GroupVersion: "apps/v1", Version: "v1"},
+			}}})
+		case "/api/v1":
+			json.NewEncoder(w).Encode(metav1.APIResourceList{GroupVersion: "v1"})
+		case "/apis/apps/v1":
+			json.NewEncoder(w).Encode(metav1.APIResourceList{GroupVersion: "apps/v1", APIResources: []metav1.APIResource{
+				{Name: "deployments", Kind: "Deployment", Namespaced: true},
+				{Name: "deployments/scale", Kind: "Scale", Namespaced: true},
+			}})
+		case "/apis/apps/v1/namespaces/test/deployments/web/scale":
+			if req.Method == http.MethodPut {
+				body, _ := ioutil.ReadAll(req.Body)
+				scale := unstructured.Unstructured{}
+				if err := scale.UnmarshalJSON(body); err != nil {
+					t.Errorf("unexpected scale %s. %s", body, err)
+				}
+				replicas, _, _ = unstructured.NestedInt64(scale.Object, "spec", "replicas")
+			}
+			fmt.Fprintf(w, `{"apiVersion": "autoscaling/v1", "kind": "Scale", "metadata": {"name": "web", "namespace": "test", "resourceVersion": "42"}, "spec": {"replicas": %d}, "status": {"replicas": %d}}`, replicas, replicas)
+		default:
+			http.NotFound(w, req)
+		}
+	}
+	return handler, func() int64 {
+		mu.Lock()
+		defer mu.Unlock()
+		return replicas
+	}
+}
+
+func TestClient_ScaleWithOptions(t *testing.T) {
+	two, three := int32(2), int32(3)
+	tests := []struct {
+		name             string
+		opt              *ScaleOptions
+		wantReplicas     int64
+		wantPrecondition bool
+	}{
+		{"no options", nil, 5, false},
+		{"replicas match", &ScaleOptions{CurrentReplicas: &three}, 5, false},
+		{"replicas mismatch", &ScaleOptions{CurrentReplicas: &two}, 3, true},
+		{"resource version mismatch", &ScaleOptions{ResourceVersion: "41"}, 3, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, replicas := scaleHandler(t)
+			c := testServerClient(t, handler)
+
+			err := c.ScaleWithOptions("deployment", "web", 5, tt.opt)
+			if _, ok := err.(ScalePreconditionError); ok != tt.wantPrecondition {
+				t.Fatalf("Client.ScaleWithOptions() error = %v, want a ScalePreconditionError %v", err, tt.wantPrecondition)
+			}
+			if !tt.wantPrecondition && err != nil {
+				t.Fatalf("Client.ScaleWithOptions() error = %v", err)
+			}
+			if got := replicas(); got != tt.wantReplicas {
+				t.Errorf("Client.ScaleWithOptions() replicas = %d, want %d", got, tt.wantReplicas)
+			}
+		})
+	}
+}
+
+func TestClient_Scale(t *testing.T) {
+	envContext := os.Getenv(contextEnvVarName)
+	envKubeconfig := os.Getenv(kubeconfigEnvVarName)
+
+	c, err := NewE(envContext, envKubeconfig)
+	if err != nil {
+		t.Fatalf("failed to create the client with context %q and kubeconfig %q", envContext, envKubeconfig)
+	}
+
+	deployment := []byte(`{"apiVersion": "apps/v1", "kind": "Deployment", "metadata": { "name": "test-scale-0" },
+		"spec": { "replicas": 1, "selector": { "matchLabels": { "app": "test-scale-0" } },
+			"template": { "metadata": { "labels": { "app": "test-scale-0" } },
+				"spec": { "containers": [ { "name": "nginx", "image": "nginx:1.17" } ] } } } }`)
+	if err := c.Apply(deployment); err != nil {
+		t.Fatalf("Client.Apply() error = %v", err)
+	}
+	defer c.Delete(deployment)
+
+	three := int32(3)
+	if err := c.ScaleWithOptions("deployment", "test-scale-0", 2, &ScaleOptions{CurrentReplicas: &three}); err == nil {
+		t.Errorf("Client.ScaleWithOptions() expected a precondition error")
+	}
+	if err := c.Scale("deployments.apps", "test-scale-0", 2); err != nil {
+		t.Fatalf("Client.Scale() error = %v", err)
+	}
+	d, err := c.Clientset.AppsV1().Deployments("default").Get("test-scale-0", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get the deployment. error = %v", err)
+	}
+	if got := *d.Spec.Replicas; got != 2 {
+		t.Errorf("Client.Scale() replicas = %d, want 2", got)
+	}
+}
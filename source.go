@@ -0,0 +1,618 @@
+package klient
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/client-go/util/homedir"
+	"sigs.k8s.io/yaml"
+)
+
+// DefaultSourceTimeout is the time to wait for the HTTP requests to fetch a
+// tarball or an OCI artifact, if SourceOptions.Timeout is not set
+const DefaultSourceTimeout = 5 * time.Minute
+
+const (
+	gitSourcePrefix = "git::"
+	ociSourcePrefix = "oci://"
+
+	ociManifestMediaType     = "application/vnd.oci.image.manifest.v1+json"
+	dockerManifestMediaType  = "application/vnd.docker.distribution.manifest.v2+json"
+	ociLayerTarGzipMediaType = "application/vnd.oci.image.layer.v1.tar+gzip"
+)
+
+// SourceOptions parameters to fetch manifests from remote sources such as Git
+// repositories, tarballs or OCI artifacts
+type SourceOptions struct {
+	// CacheDir is the directory where the fetched sources are cached. If empty,
+	// `$HOME/.kube/cache/klient` is used
+	CacheDir string
+	// Checksum is the expected checksum of a tarball, in the format
+	// `sha256:<hex>`. It can also be set with the `checksum` query parameter
+	Checksum string
+	// PlainHTTP uses HTTP instead of HTTPS to talk to the OCI registry
+	PlainHTTP bool
+	// Username and Password are the basic auth credentials for the OCI registry
+	Username string
+	Password string
+	// Timeout is the time to wait for every HTTP request. If zero,
+	// DefaultSourceTimeout is used
+	Timeout time.Duration
+}
+
+// NewSourceOptions creates a SourceOptions with the default values
+func NewSourceOptions() *SourceOptions {
+	return &SourceOptions{
+		CacheDir: filepath.Join(homedir.HomeDir(), ".kube", "cache", "klient"),
+		Timeout:  DefaultSourceTimeout,
+	}
+}
+
+// httpClient returns the client for the HTTP requests, with the timeout
+func (opt *SourceOptions) httpClient() *http.Client {
+	timeout := opt.Timeout
+	if timeout == 0 {
+		timeout = DefaultSourceTimeout
+	}
+	return &http.Client{Timeout: timeout}
+}
+
+// IsRemoteSource returns true if the given source is a Git repository, a
+// tarball or an OCI artifact reference that can be fetched with FetchSource
+func IsRemoteSource(source string) bool {
+	return strings.HasPrefix(source, gitSourcePrefix) ||
+		strings.HasPrefix(source, ociSourcePrefix) ||
+		isTarball(splitSubpath(stripQuery(source)))
+}
+
+// FetchSource resolves the given source into a stream of manifests. The
+// supported sources are:
+//   - Git repositories: `git::<repository>[//<subpath>][?ref=<ref>]`
+//   - Tarballs, local or HTTP(S): `<path or URL>.tar.gz[//<subpath>][?checksum=sha256:<hex>]`
+//   - OCI artifacts: `oci://<registry>/<repository>[:<tag>|@<digest>][//<subpath>]`
+//
+// The manifests found in the source are returned as a multi-document YAML
+func FetchSource(source string, opt *SourceOptions) ([]byte, error) {
+	if opt == nil {
+		opt = NewSourceOptions()
+	}
+	if opt.CacheDir == "" {
+		o := *opt
+		o.CacheDir = NewSourceOptions().CacheDir
+		opt = &o
+	}
+
+	switch {
+	case strings.HasPrefix(source, gitSourcePrefix):
+		return fetchGit(strings.TrimPrefix(source, gitSourcePrefix), opt)
+	case strings.HasPrefix(source, ociSourcePrefix):
+		return fetchOCI(strings.TrimPrefix(source, ociSourcePrefix), opt)
+	case IsRemoteSource(source):
+		return fetchTarball(source, opt)
+	}
+
+	return nil, fmt.Errorf("unsupported source %q", source)
+}
+
+// ResultForSource returns the builder results for the manifests in the given
+// remote source. See FetchSource for the supported sources
+func (c *Client) ResultForSource(source string, srcOpt *SourceOptions, opt *BuilderOptions) *Result {
+	content, err := FetchSource(source, srcOpt)
+	if err != nil {
+		return c.builder(opt).AddError(err).Do()
+	}
+	return c.ResultForReader(bytes.NewReader(content), opt)
+}
+
+// fetchGit clones or updates the repository in the cache, checks out the
+// requested ref and returns the manifests in the subpath
+func fetchGit(source string, opt *SourceOptions) ([]byte, error) {
+	source, query := splitQuery(source)
+	repo, subpath := splitSubpath(source)
+	ref := query.Get("ref")
+	// Arguments starting with `-` would be options of the git commands
+	if strings.HasPrefix(repo, "-") {
+		return nil, fmt.Errorf("invalid git repository %q", repo)
+	}
+	if strings.HasPrefix(ref, "-") {
+		return nil, fmt.Errorf("invalid git ref %q", ref)
+	}
+
+	dir := filepath.Join(opt.CacheDir, "git", hashOf(repo))
+	if _, err := os.Stat(filepath.Join(dir, ".git")); os.IsNotExist(err) {
+		if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+			return nil, fmt.Errorf("cannot create the cache directory. %s", err)
+		}
+		if _, err := git("", "clone", "--quiet", "--", repo, dir); err != nil {
+			return nil, err
+		}
+	} else if _, err := git(dir, "fetch", "--quiet", "--force", "--tags", "origin"); err != nil {
+		return nil, err
+	}
+
+	commit, err := gitResolveRef(dir, ref)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := git(dir, "checkout", "--quiet", "--force", "--detach", commit); err != nil {
+		return nil, err
+	}
+
+	root, err := subpathIn(dir, subpath)
+	if err != nil {
+		return nil, err
+	}
+	return readManifestsFromDir(dir, root)
+}
+
+// subpathIn returns the subpath in the directory, or an error if it's outside
+// of it, i.e. with `..` or a link to another directory
+func subpathIn(dir, subpath string) (string, error) {
+	for _, segment := range strings.Split(subpath, "/") {
+		if segment == ".." {
+			return "", fmt.Errorf("the subpath %q is outside of the repository", subpath)
+		}
+	}
+	root := filepath.Join(dir, filepath.FromSlash(path.Clean("/"+subpath)))
+
+	in, err := pathIn(dir, root)
+	if err != nil {
+		return "", fmt.Errorf("cannot find the subpath %q. %s", subpath, err)
+	}
+	if !in {
+		return "", fmt.Errorf("the subpath %q is outside of the repository", subpath)
+	}
+	return root, nil
+}
+
+// pathIn returns true if the given path, once the links are resolved, is the
+// directory or is in it
+func pathIn(dir, p string) (bool, error) {
+	realDir, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return false, err
+	}
+	realPath, err := filepath.EvalSymlinks(p)
+	if err != nil {
+		return false, err
+	}
+	return realPath == realDir || strings.HasPrefix(realPath, realDir+string(filepath.Separator)), nil
+}
+
+// gitResolveRef returns the commit of the given ref. Remote branches take
+// precedence over local refs, tags and commits. An empty ref is the remote HEAD
+func gitResolveRef(dir, ref string) (string, error) {
+	candidates := []string{"origin/HEAD"}
+	if ref != "" {
+		candidates = []string{"origin/" + ref, ref}
+	}
+	for _, candidate := range candidates {
+		if commit, err := git(dir, "rev-parse", "--verify", "--quiet", candidate+"^{commit}"); err == nil {
+			return commit, nil
+		}
+	}
+	return "", fmt.Errorf("cannot find the git ref %q", ref)
+}
+
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s failed. %s: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// fetchTarball reads a local or remote tarball, verifies its checksum if any
+// and returns the manifests in the subpath
+func fetchTarball(source string, opt *SourceOptions) ([]byte, error) {
+	source, query := splitQuery(source)
+	location, subpath := splitSubpath(source)
+	checksum := opt.Checksum
+	if c := query.Get("checksum"); c != "" {
+		checksum = c
+	}
+
+	var data []byte
+	var err error
+	if u, perr := url.Parse(location); perr == nil && (u.Scheme == "http" || u.Scheme == "https") {
+		data, err = fetchHTTP(location, checksum, opt)
+	} else {
+		data, err = ioutil.ReadFile(strings.TrimPrefix(location, "file://"))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read the tarball %q. %s", location, err)
+	}
+	if checksum != "" {
+		if err := verifyChecksum(data, checksum); err != nil {
+			return nil, fmt.Errorf("cannot verify the tarball %q. %s", location, err)
+		}
+	}
+
+	return readManifestsFromTarball(data, subpath)
+}
+
+// fetchHTTP downloads the given URL. When the checksum is known the content is
+// cached by it, so it's not downloaded again
+func fetchHTTP(location, checksum string, opt *SourceOptions) ([]byte, error) {
+	var cached string
+	if checksum != "" {
+		cached = blobPath(opt.CacheDir, checksum)
+		if data, err := ioutil.ReadFile(cached); err == nil && verifyChecksum(data, checksum) == nil {
+			return data, nil
+		}
+	}
+
+	resp, err := opt.httpClient().Get(location)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if cached != "" && verifyChecksum(data, checksum) == nil {
+		writeCache(cached, data)
+	}
+	return data, nil
+}
+
+type ociDescriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+type ociManifest struct {
+	MediaType string          `json:"mediaType"`
+	Layers    []ociDescriptor `json:"layers"`
+}
+
+// fetchOCI pulls the manifest of the given artifact reference, downloads every
+// layer and returns the manifests found in them. Layers are either tarballs or
+// plain YAML/JSON documents
+func fetchOCI(source string, opt *SourceOptions) ([]byte, error) {
+	ref, subpath := splitSubpath(source)
+	registry, repository, reference, err := parseOCIReference(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	scheme := "https"
+	if opt.PlainHTTP {
+		scheme = "http"
+	}
+	baseURL := fmt.Sprintf("%s://%s/v2/%s", scheme, registry, repository)
+
+	data, err := ociGet(baseURL+"/manifests/"+reference, opt, ociManifestMediaType, dockerManifestMediaType)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get the manifest of %q. %s", ref, err)
+	}
+	if strings.HasPrefix(reference, "sha256:") {
+		if err := verifyChecksum(data, reference); err != nil {
+			return nil, fmt.Errorf("cannot verify the manifest of %q. %s", ref, err)
+		}
+	}
+	var manifest ociManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("cannot decode the manifest of %q. %s", ref, err)
+	}
+	if len(manifest.Layers) == 0 {
+		return nil, fmt.Errorf("the artifact %q has no layers", ref)
+	}
+
+	var docs [][]byte
+	for _, layer := range manifest.Layers {
+		blob, err := fetchOCIBlob(baseURL, layer.Digest, opt)
+		if err != nil {
+			return nil, err
+		}
+		var content []byte
+		if isTarballMediaType(layer.MediaType) {
+			content, err = readManifestsFromTarball(blob, subpath)
+		} else {
+			content, err = toYAMLDocument(layer.Annotations["org.opencontainers.image.title"], blob)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot read the layer %s of %q. %s", layer.Digest, ref, err)
+		}
+		if len(content) != 0 {
+			docs = append(docs, content)
+		}
+	}
+
+	return joinDocuments(docs), nil
+}
+
+// fetchOCIBlob returns the blob with the given digest from the cache or from
+// the registry, verifying its digest
+func fetchOCIBlob(baseURL, digest string, opt *SourceOptions) ([]byte, error) {
+	cached := blobPath(opt.CacheDir, digest)
+	if data, err := ioutil.ReadFile(cached); err == nil && verifyChecksum(data, digest) == nil {
+		return data, nil
+	}
+
+	data, err := ociGet(baseURL+"/blobs/"+digest, opt)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get the blob %s. %s", digest, err)
+	}
+	if err := verifyChecksum(data, digest); err != nil {
+		return nil, fmt.Errorf("cannot verify the blob %s. %s", digest, err)
+	}
+	writeCache(cached, data)
+
+	return data, nil
+}
+
+func ociGet(location string, opt *SourceOptions, accept ...string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, location, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(accept) != 0 {
+		req.Header.Set("Accept", strings.Join(accept, ", "))
+	}
+	if opt.Username != "" || opt.Password != "" {
+		req.SetBasicAuth(opt.Username, opt.Password)
+	}
+
+	resp, err := opt.httpClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+// parseOCIReference splits a reference like `registry/repo/name:tag` or
+// `registry/repo/name@sha256:...` into its parts. The default tag is `latest`
+func parseOCIReference(ref string) (registry, repository, reference string, err error) {
+	i := strings.Index(ref, "/")
+	if i <= 0 {
+		return "", "", "", fmt.Errorf("invalid OCI reference %q, the registry is missing", ref)
+	}
+	registry, repository = ref[:i], ref[i+1:]
+
+	switch {
+	case strings.Contains(repository, "@"):
+		parts := strings.SplitN(repository, "@", 2)
+		repository, reference = parts[0], parts[1]
+	case strings.LastIndex(repository, ":") > strings.LastIndex(repository, "/"):
+		j := strings.LastIndex(repository, ":")
+		repository, reference = repository[:j], repository[j+1:]
+	default:
+		reference = "latest"
+	}
+
+	if repository == "" || reference == "" {
+		return "", "", "", fmt.Errorf("invalid OCI reference %q", ref)
+	}
+	return registry, repository, reference, nil
+}
+
+// readManifestsFromDir returns the manifests in the given file or in the files
+// of the given directory, recursively and sorted by path. The files can be
+// links only to files in the repository directory dir
+func readManifestsFromDir(dir, root string) ([]byte, error) {
+	var files []string
+	err := filepath.Walk(root, func(p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if fi.IsDir() {
+			if fi.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if p != root && !isManifestFile(p) {
+			return nil
+		}
+		if fi.Mode()&os.ModeSymlink != 0 {
+			in, err := pathIn(dir, p)
+			if err != nil {
+				return err
+			}
+			if !in {
+				return fmt.Errorf("the file %q is a link outside of the repository", strings.TrimPrefix(p, dir+string(filepath.Separator)))
+			}
+		}
+		files = append(files, p)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot read the manifests from %q. %s", root, err)
+	}
+	sort.Strings(files)
+
+	var docs [][]byte
+	for _, f := range files {
+		data, err := ioutil.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		doc, err := toYAMLDocument(f, data)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, doc)
+	}
+
+	return joinDocuments(docs), nil
+}
+
+// readManifestsFromTarball returns the manifests in the given gzipped tarball
+// under the given subpath, sorted by name
+func readManifestsFromTarball(data []byte, subpath string) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("cannot decompress the tarball. %s", err)
+	}
+	defer gz.Close()
+
+	subpath = strings.Trim(path.Clean("/"+subpath), "/")
+	files := map[string][]byte{}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot read the tarball. %s", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		name := strings.Trim(path.Clean("/"+hdr.Name), "/")
+		if subpath != "" && name != subpath && !strings.HasPrefix(name, subpath+"/") {
+			continue
+		}
+		if name != subpath && !isManifestFile(name) {
+			continue
+		}
+		content, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read %q from the tarball. %s", hdr.Name, err)
+		}
+		files[name] = content
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var docs [][]byte
+	for _, name := range names {
+		doc, err := toYAMLDocument(name, files[name])
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, doc)
+	}
+
+	return joinDocuments(docs), nil
+}
+
+// toYAMLDocument converts JSON files to YAML so they can be joined in a single
+// multi-document YAML stream
+func toYAMLDocument(name string, data []byte) ([]byte, error) {
+	trimmed := bytes.TrimSpace(data)
+	if strings.HasSuffix(name, ".json") || bytes.HasPrefix(trimmed, []byte("{")) {
+		doc, err := yaml.JSONToYAML(trimmed)
+		if err != nil {
+			return nil, fmt.Errorf("cannot convert %q to YAML. %s", name, err)
+		}
+		return doc, nil
+	}
+	return trimmed, nil
+}
+
+func joinDocuments(docs [][]byte) []byte {
+	return bytes.Join(docs, []byte("\n---\n"))
+}
+
+func verifyChecksum(data []byte, checksum string) error {
+	parts := strings.SplitN(checksum, ":", 2)
+	if len(parts) != 2 || parts[0] != "sha256" {
+		return fmt.Errorf("unsupported checksum %q, expected sha256:<hex>", checksum)
+	}
+	sum := sha256.Sum256(data)
+	if got := hex.EncodeToString(sum[:]); got != strings.ToLower(parts[1]) {
+		return fmt.Errorf("checksum mismatch, expected %s, got sha256:%s", checksum, got)
+	}
+	return nil
+}
+
+func blobPath(cacheDir, digest string) string {
+	return filepath.Join(cacheDir, "blobs", strings.Replace(digest, ":", string(filepath.Separator), 1))
+}
+
+// writeCache stores the data in the cache. A failure to cache is not an error
+func writeCache(file string, data []byte) {
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return
+	}
+	ioutil.WriteFile(file, data, 0644)
+}
+
+func hashOf(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// splitSubpath splits a source like `<location>//<subpath>` ignoring the `//`
+// of the URL scheme
+func splitSubpath(source string) (string, string) {
+	offset := 0
+	if i := strings.Index(source, "://"); i >= 0 {
+		offset = i + 3
+	}
+	if i := strings.Index(source[offset:], "//"); i >= 0 {
+		return source[:offset+i], source[offset+i+2:]
+	}
+	return source, ""
+}
+
+func splitQuery(source string) (string, url.Values) {
+	i := strings.LastIndex(source, "?")
+	if i < 0 {
+		return source, url.Values{}
+	}
+	query, err := url.ParseQuery(source[i+1:])
+	if err != nil {
+		return source, url.Values{}
+	}
+	return source[:i], query
+}
+
+func stripQuery(source string) string {
+	s, _ := splitQuery(source)
+	return s
+}
+
+func isTarball(location, _ string) bool {
+	return strings.HasSuffix(location, ".tar.gz") || strings.HasSuffix(location, ".tgz")
+}
+
+func isTarballMediaType(mediaType string) bool {
+	return mediaType == ociLayerTarGzipMediaType || strings.HasSuffix(mediaType, ".tar+gzip") || strings.HasSuffix(mediaType, "tar.gzip")
+}
+
+func isManifestFile(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,256 @@
+package klient
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testTarball(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		hdr := &tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tw.Close()
+	gz.Close()
+	return buf.Bytes()
+}
+
+func testDigest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func testGitRepo(t *testing.T, dir string) string {
+	run := func(dir string, args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com", "GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %s. %s", args, err, out)
+		}
+	}
+
+	bare := filepath.Join(dir, "repo.git")
+	work := filepath.Join(dir, "work")
+	run(dir, "init", "--quiet", "--bare", bare)
+	run(dir, "clone", "--quiet", bare, work)
+
+	os.MkdirAll(filepath.Join(work, "manifests"), 0755)
+	ioutil.WriteFile(filepath.Join(work, "manifests", "cm.yaml"), []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: v1\n"), 0644)
+	ioutil.WriteFile(filepath.Join(work, "README.md"), []byte("# test"), 0644)
+	run(work, "add", ".")
+	run(work, "commit", "--quiet", "-m", "v1")
+	run(work, "tag", "v1")
+	ioutil.WriteFile(filepath.Join(work, "manifests", "cm.yaml"), []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: v2\n"), 0644)
+	ioutil.WriteFile(filepath.Join(work, "manifests", "secret.json"), []byte(`{"apiVersion": "v1", "kind": "Secret", "metadata": {"name": "s2"}}`), 0644)
+	os.Symlink(dir, filepath.Join(work, "outside"))
+	os.MkdirAll(filepath.Join(work, "linked"), 0755)
+	os.Symlink(filepath.Join("..", "manifests", "cm.yaml"), filepath.Join(work, "linked", "cm.yaml"))
+	ioutil.WriteFile(filepath.Join(dir, "secret.yaml"), []byte("apiVersion: v1\nkind: Secret\nmetadata:\n  name: leaked\n"), 0644)
+	os.MkdirAll(filepath.Join(work, "leaked"), 0755)
+	os.Symlink(filepath.Join(dir, "secret.yaml"), filepath.Join(work, "leaked", "secret.yaml"))
+	run(work, "add", ".")
+	run(work, "commit", "--quiet", "-m", "v2")
+	run(work, "push", "--quiet", "--tags", "origin", "HEAD")
+
+	return bare
+}
+
+func TestFetchSource_git(t *testing.T) {
+	dir, err := ioutil.TempDir("", "klient-git")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	repo := testGitRepo(t, dir)
+	opt := &SourceOptions{CacheDir: filepath.Join(dir, "cache")}
+
+	tests := []struct {
+		name         string
+		source       string
+		wantContains []string
+		wantMissing  []string
+		wantErr      bool
+	}{
+		{"HEAD", "git::" + repo + "//manifests", []string{"name: v2", "name: s2"}, []string{"# test"}, false},
+		{"tag", "git::" + repo + "//manifests?ref=v1", []string{"name: v1"}, []string{"name: v2", "name: s2"}, false},
+		{"single file", "git::" + repo + "//manifests/cm.yaml?ref=v1", []string{"name: v1"}, nil, false},
+		{"unknown ref", "git::" + repo + "?ref=v9", nil, nil, true},
+		{"option as repository", "git::--upload-pack=touch /tmp/klient-pwned", nil, nil, true},
+		{"option as ref", "git::" + repo + "?ref=--output=/tmp/klient-pwned", nil, nil, true},
+		{"subpath outside", "git::" + repo + "//../../..", nil, nil, true},
+		{"link outside", "git::" + repo + "//outside", nil, nil, true},
+		{"file link in the repository", "git::" + repo + "//linked", []string{"name: v2"}, nil, false},
+		{"file link outside", "git::" + repo + "//leaked", nil, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FetchSource(tt.source, opt)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FetchSource() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for _, want := range tt.wantContains {
+				if !strings.Contains(string(got), want) {
+					t.Errorf("FetchSource() = %q, does not contain %q", got, want)
+				}
+			}
+			for _, missing := range tt.wantMissing {
+				if strings.Contains(string(got), missing) {
+					t.Errorf("FetchSource() = %q, should not contain %q", got, missing)
+				}
+			}
+		})
+	}
+}
+
+func TestFetchSource_tarball(t *testing.T) {
+	dir, err := ioutil.TempDir("", "klient-tarball")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tarball := testTarball(t, map[string]string{
+		"app/cm.yaml":   "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm\n",
+		"app/notes.txt": "not a manifest",
+		"other/s.json":  `{"apiVersion": "v1", "kind": "Secret", "metadata": {"name": "s"}}`,
+	})
+	filename := filepath.Join(dir, "manifests.tar.gz")
+	if err := ioutil.WriteFile(filename, tarball, 0644); err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(http.FileServer(http.Dir(dir)))
+	defer ts.Close()
+	opt := &SourceOptions{CacheDir: filepath.Join(dir, "cache")}
+
+	tests := []struct {
+		name         string
+		source       string
+		wantContains []string
+		wantMissing  []string
+		wantErr      bool
+	}{
+		{"local", filename, []string{"name: cm", "name: s"}, []string{"not a manifest"}, false},
+		{"local subpath", filename + "//app", []string{"name: cm"}, []string{"name: s"}, false},
+		{"http with checksum", ts.URL + "/manifests.tar.gz?checksum=" + testDigest(tarball), []string{"name: cm", "name: s"}, nil, false},
+		{"http wrong checksum", ts.URL + "/manifests.tar.gz?checksum=" + testDigest([]byte("x")), nil, nil, true},
+		{"not found", ts.URL + "/missing.tar.gz", nil, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FetchSource(tt.source, opt)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FetchSource() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for _, want := range tt.wantContains {
+				if !strings.Contains(string(got), want) {
+					t.Errorf("FetchSource() = %q, does not contain %q", got, want)
+				}
+			}
+			for _, missing := range tt.wantMissing {
+				if strings.Contains(string(got), missing) {
+					t.Errorf("FetchSource() = %q, should not contain %q", got, missing)
+				}
+			}
+		})
+	}
+}
+
+func TestFetchSource_timeout(t *testing.T) {
+	done := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer ts.Close()
+	defer close(done)
+
+	dir, err := ioutil.TempDir("", "klient-timeout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	opt := &SourceOptions{CacheDir: dir, Timeout: 50 * time.Millisecond}
+	if _, err := FetchSource(ts.URL+"/manifests.tar.gz", opt); err == nil {
+		t.Errorf("FetchSource() error = nil, want a timeout")
+	}
+}
+
+func TestFetchSource_oci(t *testing.T) {
+	dir, err := ioutil.TempDir("", "klient-oci")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	layer := testTarball(t, map[string]string{"cm.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: oci\n"})
+	manifest, _ := json.Marshal(ociManifest{
+		MediaType: ociManifestMediaType,
+		Layers:    []ociDescriptor{{MediaType: ociLayerTarGzipMediaType, Digest: testDigest(layer), Size: int64(len(layer))}},
+	})
+	missing, _ := json.Marshal(ociManifest{
+		MediaType: ociManifestMediaType,
+		Layers:    []ociDescriptor{{MediaType: ociLayerTarGzipMediaType, Digest: testDigest([]byte("missing"))}},
+	})
+	blobs := map[string][]byte{
+		"/v2/apps/web/manifests/1.0":                     manifest,
+		"/v2/apps/web/manifests/" + testDigest(manifest): manifest,
+		"/v2/apps/web/blobs/" + testDigest(layer):        layer,
+		"/v2/apps/corrupt/manifests/latest":              missing,
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, ok := blobs[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(data)
+	}))
+	defer ts.Close()
+	registry := strings.TrimPrefix(ts.URL, "http://")
+	opt := &SourceOptions{CacheDir: filepath.Join(dir, "cache"), PlainHTTP: true}
+
+	tests := []struct {
+		name    string
+		source  string
+		want    string
+		wantErr bool
+	}{
+		{"tag", fmt.Sprintf("oci://%s/apps/web:1.0", registry), "name: oci", false},
+		{"digest", fmt.Sprintf("oci://%s/apps/web@%s", registry, testDigest(manifest)), "name: oci", false},
+		{"missing blob", fmt.Sprintf("oci://%s/apps/corrupt", registry), "", true},
+		{"missing tag", fmt.Sprintf("oci://%s/apps/web:2.0", registry), "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FetchSource(tt.source, opt)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FetchSource() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !strings.Contains(string(got), tt.want) {
+				t.Errorf("FetchSource() = %q, does not contain %q", got, tt.want)
+			}
+		})
+	}
+}
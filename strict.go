@@ -0,0 +1,182 @@
+package klient
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// ValidateStrict validates the content like Validate and also rejects the
+// duplicate keys, the empty documents and the unknown fields in custom
+// resources even if the CRD preserve them. The source, i.e. a filename, is
+// used in the errors to locate the failure. A trailing document separator
+// at the end of the content is not considered an empty document
+func (v *Validator) ValidateStrict(content []byte, source string) error {
+	docs, err := decodeDocuments(content)
+	if err != nil {
+		return withSource(err, source)
+	}
+
+	var errs ValidationErrors
+	if len(docs) == 0 {
+		errs = append(errs, ValidationError{Source: source, Message: "there are no objects"})
+	}
+	for i, doc := range docs {
+		if doc.obj == nil {
+			if i == len(docs)-1 && i != 0 {
+				continue
+			}
+			err := doc.errorf("", "empty document")
+			errs = append(errs, err)
+			continue
+		}
+		errs = append(errs, duplicateKeys(doc, doc.node.Content[0], "")...)
+		errs = append(errs, v.validateDocumentStrict(doc)...)
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	sortValidationErrors(errs)
+	return withSource(errs, source)
+}
+
+func (v *Validator) validateDocumentStrict(doc *document) ValidationErrors {
+	errs := v.validateDocument(doc)
+
+	// The CRDs may preserve unknown fields, in strict mode they are rejected
+	crd, ok := v.crds[doc.gvk]
+	if !ok || !crd.preserveUnknownFields {
+		return errs
+	}
+	found := map[string]bool{}
+	for _, err := range errs {
+		found[err.Field] = true
+	}
+	for _, fe := range validateCustomResource(doc.obj, crd, true) {
+		if fe.message != "unknown field" || found[fe.field] {
+			continue
+		}
+		err := doc.errorf(fe.field, fe.message)
+		err.Kind, err.Name = doc.gvk.Kind, objectName(doc.obj)
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// duplicateKeys returns an error for every key defined more than once in the
+// mappings of the given node
+func duplicateKeys(doc *document, node *yamlv3.Node, field string) ValidationErrors {
+	var errs ValidationErrors
+	switch node.Kind {
+	case yamlv3.MappingNode:
+		seen := map[string]int{}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i]
+			child := joinField(field, key.Value)
+			if line, ok := seen[key.Value]; ok {
+				err := doc.errorf(child, fmt.Sprintf("duplicate key, already defined at line %d", line))
+				err.Line = key.Line
+				err.Name = objectName(doc.obj)
+				errs = append(errs, err)
+			} else {
+				seen[key.Value] = key.Line
+			}
+			errs = append(errs, duplicateKeys(doc, node.Content[i+1], child)...)
+		}
+	case yamlv3.SequenceNode:
+		for i, item := range node.Content {
+			errs = append(errs, duplicateKeys(doc, item, fmt.Sprintf("%s[%d]", field, i))...)
+		}
+	}
+	return errs
+}
+
+// withSource sets the source of the validation errors
+func withSource(err error, source string) error {
+	errs, ok := err.(ValidationErrors)
+	if !ok || source == "" {
+		return err
+	}
+	for i := range errs {
+		errs[i].Source = source
+	}
+	return errs
+}
+
+// validateStrict validates in strict mode the content read from the source
+func (c *Client) validateStrict(content []byte, source string) error {
+	v, err := c.schemaValidator()
+	if err != nil {
+		return err
+	}
+	return v.ValidateStrict(content, source)
+}
+
+// readManifests reads the given files, directories or URLs and validates them
+// with the given function. The directories are not read recursively
+func (c *Client) readManifests(filename string, srcOpt *SourceOptions, validate func([]byte, string) error) ([]byte, error) {
+	var content []byte
+	var err error
+	switch {
+	case IsRemoteSource(filename):
+		content, err = FetchSource(filename, srcOpt)
+	case strings.HasPrefix(filename, "http://") || strings.HasPrefix(filename, "https://"):
+		content, err = fetchHTTP(filename, "", NewSourceOptions())
+	case filename == "-":
+		content, err = ioutil.ReadAll(os.Stdin)
+	default:
+		return readStrictPath(filename, validate)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read %q. %s", filename, err)
+	}
+	if err := validate(content, filename); err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+// readStrictPath reads and validates every file in the path, returning the
+// content of all of them
+func readStrictPath(path string, validate func([]byte, string) error) ([]byte, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{path}
+	if fi.IsDir() {
+		entries, err := ioutil.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+		files = nil
+		for _, entry := range entries {
+			if !entry.IsDir() && isManifestFile(entry.Name()) {
+				files = append(files, filepath.Join(path, entry.Name()))
+			}
+		}
+	}
+
+	var docs [][]byte
+	for _, f := range files {
+		content, err := ioutil.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		if err := validate(content, f); err != nil {
+			return nil, err
+		}
+		doc, err := toYAMLDocument(f, content)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, doc)
+	}
+	return joinDocuments(docs), nil
+}
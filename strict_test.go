@@ -0,0 +1,75 @@
+package klient
+
+import (
+	"io/ioutil"
+	"testing"
+)
+
+func TestValidator_ValidateStrict(t *testing.T) {
+	crd, err := ioutil.ReadFile("./testdata/validate/crd.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := NewValidatorForVersion("v1.16")
+	if err != nil {
+		t.Fatalf("NewValidatorForVersion() error = %v", err)
+	}
+	if err := v.AddCRDs(crd); err != nil {
+		t.Fatalf("Validator.AddCRDs() error = %v", err)
+	}
+	legacy := []byte("apiVersion: apiextensions.k8s.io/v1beta1\nkind: CustomResourceDefinition\nmetadata:\n  name: veggies.example.com\nspec:\n  group: example.com\n  version: v1\n  names:\n    kind: Veggie\n    plural: veggies\n  scope: Namespaced\n  validation:\n    openAPIV3Schema:\n      type: object\n      properties:\n        spec:\n          type: object\n          properties:\n            color:\n              type: string\n")
+	if err := v.AddCRDs(legacy); err != nil {
+		t.Fatalf("Validator.AddCRDs() error = %v", err)
+	}
+
+	carrot := []byte("apiVersion: example.com/v1\nkind: Veggie\nmetadata:\n  name: carrot\nspec:\n  colour: orange\n")
+	if err := v.Validate(carrot); err != nil {
+		t.Errorf("Validator.Validate() error = %v, the preserved unknown fields are valid if not strict", err)
+	}
+
+	tests := []struct {
+		name    string
+		content []byte
+		want    []ValidationError
+	}{
+		{"valid", []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n---\n"), nil},
+		{"duplicate keys", []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\ndata:\n  k: v1\n  k: v2\n"), []ValidationError{
+			{Source: "cm.yaml", Index: 0, Line: 7, Kind: "ConfigMap", Name: "a", Field: "data.k", Message: "duplicate key, already defined at line 6"},
+		}},
+		{"empty document", []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n---\n---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: b\n"), []ValidationError{
+			{Source: "cm.yaml", Index: 1, Line: 6, Message: "empty document"},
+		}},
+		{"no objects", []byte(""), []ValidationError{
+			{Source: "cm.yaml", Message: "there are no objects"},
+		}},
+		{"typo", []byte("apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: a\nspec:\n  replica: 2\n  selector: {}\n  template: {}\n"), []ValidationError{
+			{Source: "cm.yaml", Index: 0, Line: 6, Kind: "Deployment", Name: "a", Field: "spec.replica", Message: "unknown field"},
+		}},
+		{"preserved unknown field", carrot, []ValidationError{
+			{Source: "cm.yaml", Index: 0, Line: 6, Kind: "Veggie", Name: "carrot", Field: "spec.colour", Message: "unknown field"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.ValidateStrict(tt.content, "cm.yaml")
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("Validator.ValidateStrict() error = %v, want nil", err)
+				}
+				return
+			}
+			got, ok := err.(ValidationErrors)
+			if !ok {
+				t.Fatalf("Validator.ValidateStrict() error = %v, want ValidationErrors", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Validator.ValidateStrict() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Validator.ValidateStrict() error[%d] = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,695 @@
+package klient
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	openapi_v2 "github.com/googleapis/gnostic/OpenAPIv2"
+	"github.com/googleapis/gnostic/compiler"
+	yamlv3 "gopkg.in/yaml.v3"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/json"
+	protovalidation "k8s.io/kube-openapi/pkg/util/proto/validation"
+	"k8s.io/kubectl/pkg/util/openapi"
+	"k8s.io/kubectl/pkg/validation"
+	"sigs.k8s.io/yaml"
+)
+
+// bundledOpenAPISchemas are the compressed OpenAPI v2 documents bundled with
+// klient, by Kubernetes version (i.e. `1.16`). Generated by hack/bundle-openapi.go
+var bundledOpenAPISchemas = map[string]string{}
+
+var (
+	bundledValidatorsMu sync.Mutex
+	bundledResources    = map[string]openapi.Resources{}
+)
+
+// ValidationError is a validation failure of an object or one of its fields
+type ValidationError struct {
+	// Source is the file or URL with the document, if known
+	Source string
+	// Index is the position of the document in the input, starting at 0
+	Index int
+	// Line is the line of the field in the input, starting at 1. It's 0 if unknown
+	Line int
+	Kind string
+	Name string
+	// Field is the path to the field, i.e. `spec.template.spec.containers[0].image`
+	Field   string
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	var location string
+	if e.Line > 0 {
+		location = fmt.Sprintf(" (line %d)", e.Line)
+	}
+	var object string
+	if e.Kind != "" {
+		object += ", " + e.Kind
+	}
+	if e.Name != "" {
+		object += " " + strconv.Quote(e.Name)
+	}
+	field := e.Field
+	if field != "" {
+		field = " " + field + ":"
+	}
+	var source string
+	if e.Source != "" {
+		source = e.Source + ": "
+	}
+	return fmt.Sprintf("%sdocument %d%s%s:%s %s", source, e.Index, location, object, field, e.Message)
+}
+
+// ValidationErrors is the list of validation failures of an input
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+	return fmt.Sprintf("validation failed: %s", strings.Join(msgs, "; "))
+}
+
+// Validator validates manifests against OpenAPI schemas and CRD schemas
+// without the need of a cluster. It implements the validation.Schema
+// interface so it can be used by the Client with SetValidator
+type Validator struct {
+	resources openapi.Resources
+	crds      map[schema.GroupVersionKind]*crdSchema
+}
+
+type crdSchema struct {
+	schema                *apiextv1.JSONSchemaProps
+	preserveUnknownFields bool
+}
+
+var _ validation.Schema = &Validator{}
+
+// NewValidator creates a Validator for the given OpenAPI resources
+func NewValidator(resources openapi.Resources) *Validator {
+	return &Validator{
+		resources: resources,
+		crds:      map[schema.GroupVersionKind]*crdSchema{},
+	}
+}
+
+// NewValidatorFromFile creates a Validator loading the OpenAPI v2 document,
+// JSON or YAML, from the given file
+func NewValidatorFromFile(filename string) (*Validator, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	resources, err := parseOpenAPIDocument(data)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load the OpenAPI document %q. %s", filename, err)
+	}
+	return NewValidator(resources), nil
+}
+
+// NewValidatorForVersion creates a Validator using the OpenAPI document bundled
+// for the given Kubernetes version, i.e. `v1.16` or `1.16.3`
+func NewValidatorForVersion(version string) (*Validator, error) {
+	version = majorMinor(version)
+
+	bundledValidatorsMu.Lock()
+	defer bundledValidatorsMu.Unlock()
+
+	if resources, ok := bundledResources[version]; ok {
+		return NewValidator(resources), nil
+	}
+
+	encoded, ok := bundledOpenAPISchemas[version]
+	if !ok {
+		return nil, fmt.Errorf("there is no OpenAPI schema bundled for version %q, the bundled versions are %v", version, BundledSchemaVersions())
+	}
+	compressed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	gz, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadAll(gz)
+	if err != nil {
+		return nil, err
+	}
+	resources, err := parseOpenAPIDocument(data)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load the OpenAPI document for version %q. %s", version, err)
+	}
+	bundledResources[version] = resources
+
+	return NewValidator(resources), nil
+}
+
+// BundledSchemaVersions returns the Kubernetes versions with a bundled OpenAPI schema
+func BundledSchemaVersions() []string {
+	versions := make([]string, 0, len(bundledOpenAPISchemas))
+	for v := range bundledOpenAPISchemas {
+		versions = append(versions, v)
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+func parseOpenAPIDocument(data []byte) (openapi.Resources, error) {
+	// The compiler caches the documents by filename
+	info, err := compiler.ReadInfoFromBytes(hashOf(string(data)), data)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := openapi_v2.NewDocument(info, compiler.NewContext("$root", nil))
+	if err != nil {
+		return nil, err
+	}
+	return openapi.NewOpenAPIData(doc)
+}
+
+// majorMinor returns the major and minor numbers of a version like `v1.16.3`
+func majorMinor(version string) string {
+	parts := strings.SplitN(strings.TrimPrefix(version, "v"), ".", 3)
+	if len(parts) < 2 {
+		return strings.Join(parts, ".")
+	}
+	return parts[0] + "." + strings.TrimRight(parts[1], "+")
+}
+
+// AddCRDs loads the schemas of the CustomResourceDefinitions, v1 or v1beta1,
+// in the given content so the custom resources can be validated
+func (v *Validator) AddCRDs(content []byte) error {
+	docs, err := decodeDocuments(content)
+	if err != nil {
+		return err
+	}
+	for _, doc := range docs {
+		if doc.obj == nil || doc.gvk.Kind != "CustomResourceDefinition" {
+			continue
+		}
+		data, err := json.Marshal(doc.obj)
+		if err != nil {
+			return err
+		}
+
+		switch doc.gvk.GroupVersion() {
+		case apiextv1.SchemeGroupVersion:
+			var crd apiextv1.CustomResourceDefinition
+			if err := json.Unmarshal(data, &crd); err != nil {
+				return fmt.Errorf("cannot decode the CRD in document %d. %s", doc.index, err)
+			}
+			for _, version := range crd.Spec.Versions {
+				if version.Schema == nil || version.Schema.OpenAPIV3Schema == nil {
+					continue
+				}
+				gvk := schema.GroupVersionKind{Group: crd.Spec.Group, Version: version.Name, Kind: crd.Spec.Names.Kind}
+				v.crds[gvk] = &crdSchema{schema: version.Schema.OpenAPIV3Schema, preserveUnknownFields: crd.Spec.PreserveUnknownFields}
+			}
+		case apiextv1beta1.SchemeGroupVersion:
+			var crd apiextv1beta1.CustomResourceDefinition
+			if err := json.Unmarshal(data, &crd); err != nil {
+				return fmt.Errorf("cannot decode the CRD in document %d. %s", doc.index, err)
+			}
+			// The preserveUnknownFields default value in v1beta1 is true
+			preserve := crd.Spec.PreserveUnknownFields == nil || *crd.Spec.PreserveUnknownFields
+			versions := crd.Spec.Versions
+			if len(versions) == 0 {
+				versions = []apiextv1beta1.CustomResourceDefinitionVersion{{Name: crd.Spec.Version}}
+			}
+			for _, version := range versions {
+				validation := crd.Spec.Validation
+				if version.Schema != nil {
+					validation = version.Schema
+				}
+				if validation == nil || validation.OpenAPIV3Schema == nil {
+					continue
+				}
+				// The JSON schema is the same in v1beta1 and v1
+				var props apiextv1.JSONSchemaProps
+				data, err := json.Marshal(validation.OpenAPIV3Schema)
+				if err != nil {
+					return err
+				}
+				if err := json.Unmarshal(data, &props); err != nil {
+					return err
+				}
+				gvk := schema.GroupVersionKind{Group: crd.Spec.Group, Version: version.Name, Kind: crd.Spec.Names.Kind}
+				v.crds[gvk] = &crdSchema{schema: &props, preserveUnknownFields: preserve}
+			}
+		}
+	}
+
+	return nil
+}
+
+// ValidateBytes validates a single document. It's required to implement the
+// validation.Schema interface
+func (v *Validator) ValidateBytes(data []byte) error {
+	return v.Validate(data)
+}
+
+// Validate validates every object in the given content, YAML or JSON with one
+// or multiple documents. If the content is not valid the returned error is a
+// ValidationErrors with the failures of every object
+func (v *Validator) Validate(content []byte) error {
+	docs, err := decodeDocuments(content)
+	if err != nil {
+		return err
+	}
+
+	var errs ValidationErrors
+	for _, doc := range docs {
+		if doc.obj == nil {
+			continue
+		}
+		errs = append(errs, v.validateDocument(doc)...)
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	sortValidationErrors(errs)
+	return errs
+}
+
+func (v *Validator) validateDocument(doc *document) ValidationErrors {
+	if (doc.gvk == schema.GroupVersionKind{Version: "v1", Kind: "List"}) {
+		var errs ValidationErrors
+		items, _ := doc.obj["items"].([]interface{})
+		for i, item := range items {
+			obj, ok := item.(map[string]interface{})
+			if !ok {
+				errs = append(errs, doc.errorf(fmt.Sprintf("items[%d]", i), "invalid object to validate"))
+				continue
+			}
+			errs = append(errs, v.validateObject(doc, obj, fmt.Sprintf("items[%d].", i))...)
+		}
+		return errs
+	}
+
+	return v.validateObject(doc, doc.obj, "")
+}
+
+func (v *Validator) validateObject(doc *document, obj map[string]interface{}, prefix string) ValidationErrors {
+	gvk, kindErrs := objectKind(obj)
+	name := objectName(obj)
+	var errs ValidationErrors
+	for _, msg := range kindErrs {
+		err := doc.errorf(strings.TrimSuffix(prefix, "."), msg)
+		err.Name = name
+		errs = append(errs, err)
+	}
+	if len(errs) != 0 {
+		return errs
+	}
+
+	var fieldErrs []fieldError
+	if crd, ok := v.crds[gvk]; ok {
+		fieldErrs = validateCustomResource(obj, crd, false)
+	} else if v.resources != nil {
+		if model := v.resources.LookupResource(gvk); model != nil {
+			fieldErrs = fromProtoErrors(protovalidation.ValidateModel(obj, model, gvk.Kind))
+		}
+	}
+
+	for _, fe := range fieldErrs {
+		err := doc.errorf(prefix+fe.field, fe.message)
+		err.Kind, err.Name = gvk.Kind, name
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+func sortValidationErrors(errs ValidationErrors) {
+	sort.SliceStable(errs, func(i, j int) bool {
+		if errs[i].Index != errs[j].Index {
+			return errs[i].Index < errs[j].Index
+		}
+		if errs[i].Line != errs[j].Line {
+			return errs[i].Line < errs[j].Line
+		}
+		return errs[i].Field < errs[j].Field
+	})
+}
+
+// fieldError is a validation failure of a field
+type fieldError struct {
+	field   string
+	message string
+}
+
+// fromProtoErrors converts the errors of the OpenAPI validation to field errors.
+// The paths of the OpenAPI validation start with the Kind, it's removed.
+func fromProtoErrors(errs []error) []fieldError {
+	fieldErrs := []fieldError{}
+	for _, err := range errs {
+		verr, ok := err.(protovalidation.ValidationError)
+		if !ok {
+			fieldErrs = append(fieldErrs, fieldError{message: err.Error()})
+			continue
+		}
+		field := verr.Path
+		if i := strings.IndexAny(field, ".["); i >= 0 {
+			field = strings.TrimPrefix(field[i:], ".")
+		} else {
+			field = ""
+		}
+
+		var message string
+		switch e := verr.Err.(type) {
+		case protovalidation.UnknownFieldError:
+			field = joinField(field, e.Field)
+			message = "unknown field"
+		case protovalidation.MissingRequiredFieldError:
+			field = joinField(field, e.Field)
+			message = "missing required field"
+		case protovalidation.InvalidTypeError:
+			message = fmt.Sprintf("invalid type, got %q, expected %q", e.Actual, e.Expected)
+		case protovalidation.InvalidObjectTypeError:
+			message = fmt.Sprintf("invalid object type %q", e.Type)
+		default:
+			message = verr.Err.Error()
+		}
+		fieldErrs = append(fieldErrs, fieldError{field: field, message: message})
+	}
+	return fieldErrs
+}
+
+func joinField(parent, field string) string {
+	if parent == "" {
+		return field
+	}
+	return parent + "." + field
+}
+
+// validateCustomResource validates the custom resource with the structural
+// schema of the CRD. In strict mode the unknown fields are rejected even if the
+// CRD preserve them
+func validateCustomResource(obj map[string]interface{}, crd *crdSchema, strict bool) []fieldError {
+	s := *crd.schema
+	// apiVersion, kind and metadata are implicit in the CRD schemas
+	props := map[string]apiextv1.JSONSchemaProps{
+		"apiVersion": {Type: "string"},
+		"kind":       {Type: "string"},
+		"metadata":   {Type: "object", XPreserveUnknownFields: boolPtr(true)},
+	}
+	for k, p := range s.Properties {
+		props[k] = p
+	}
+	s.Properties = props
+
+	errs := validateJSONSchema("", obj, &s, strict)
+	if !crd.preserveUnknownFields || strict {
+		return errs
+	}
+
+	// The unknown fields are preserved in the entire object
+	known := []fieldError{}
+	for _, err := range errs {
+		if err.message != "unknown field" {
+			known = append(known, err)
+		}
+	}
+	return known
+}
+
+// validateJSONSchema validates the value with the given structural schema
+func validateJSONSchema(field string, value interface{}, s *apiextv1.JSONSchemaProps, strict bool) []fieldError {
+	if s == nil {
+		return nil
+	}
+	if value == nil {
+		if s.Nullable || s.Type == "" {
+			return nil
+		}
+		return []fieldError{{field, fmt.Sprintf("invalid type, got null, expected %q", s.Type)}}
+	}
+
+	var errs []fieldError
+	if s.XIntOrString {
+		switch value.(type) {
+		case string, int64, float64:
+			return nil
+		}
+		return []fieldError{{field, fmt.Sprintf("invalid type, got %q, expected integer or string", jsonType(value))}}
+	}
+
+	switch s.Type {
+	case "object":
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return []fieldError{{field, fmt.Sprintf("invalid type, got %q, expected \"object\"", jsonType(value))}}
+		}
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			child := joinField(field, k)
+			if p, ok := s.Properties[k]; ok {
+				errs = append(errs, validateJSONSchema(child, m[k], &p, strict)...)
+				continue
+			}
+			if s.AdditionalProperties != nil {
+				if s.AdditionalProperties.Schema != nil {
+					errs = append(errs, validateJSONSchema(child, m[k], s.AdditionalProperties.Schema, strict)...)
+					continue
+				}
+				if s.AdditionalProperties.Allows {
+					continue
+				}
+			}
+			preserve := s.XPreserveUnknownFields != nil && *s.XPreserveUnknownFields
+			if s.XEmbeddedResource && (k == "apiVersion" || k == "kind" || k == "metadata") {
+				continue
+			}
+			if !preserve || (strict && len(s.Properties) != 0) {
+				errs = append(errs, fieldError{child, "unknown field"})
+			}
+		}
+		for _, r := range s.Required {
+			if _, ok := m[r]; !ok {
+				errs = append(errs, fieldError{joinField(field, r), "missing required field"})
+			}
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return []fieldError{{field, fmt.Sprintf("invalid type, got %q, expected \"array\"", jsonType(value))}}
+		}
+		if s.Items != nil && s.Items.Schema != nil {
+			for i, item := range items {
+				errs = append(errs, validateJSONSchema(fmt.Sprintf("%s[%d]", field, i), item, s.Items.Schema, strict)...)
+			}
+		}
+	case "string", "boolean", "number", "integer":
+		if got := jsonType(value); got != s.Type && !(s.Type == "number" && got == "integer") {
+			return []fieldError{{field, fmt.Sprintf("invalid type, got %q, expected %q", got, s.Type)}}
+		}
+	}
+
+	if len(s.Enum) != 0 {
+		var found bool
+		for _, e := range s.Enum {
+			var allowed interface{}
+			if err := json.Unmarshal(e.Raw, &allowed); err == nil && reflect.DeepEqual(allowed, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			errs = append(errs, fieldError{field, fmt.Sprintf("unsupported value %v", value)})
+		}
+	}
+
+	return errs
+}
+
+func jsonType(value interface{}) string {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case int64:
+		return "integer"
+	case float64:
+		if v == float64(int64(v)) {
+			return "integer"
+		}
+		return "number"
+	case nil:
+		return "null"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+// document is a document of a multi-document input, with the decoded object
+// and the YAML nodes to locate the fields in the input
+type document struct {
+	index int
+	node  *yamlv3.Node
+	obj   map[string]interface{}
+	gvk   schema.GroupVersionKind
+}
+
+var yamlErrorLine = regexp.MustCompile(`line (\d+)`)
+
+// decodeDocuments splits the YAML or JSON content in documents. The empty
+// documents have a nil object
+func decodeDocuments(content []byte) ([]*document, error) {
+	var docs []*document
+	decoder := yamlv3.NewDecoder(bytes.NewReader(content))
+	for index := 0; ; index++ {
+		var node yamlv3.Node
+		err := decoder.Decode(&node)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			verr := ValidationError{Index: index, Message: strings.TrimPrefix(err.Error(), "yaml: ")}
+			if m := yamlErrorLine.FindStringSubmatch(err.Error()); m != nil {
+				verr.Line, _ = strconv.Atoi(m[1])
+			}
+			return nil, ValidationErrors{verr}
+		}
+
+		doc := &document{index: index, node: &node}
+		docs = append(docs, doc)
+		if len(node.Content) == 0 || node.Content[0].Tag == "!!null" {
+			continue
+		}
+
+		obj, err := nodeToObject(&node)
+		if err != nil {
+			return nil, ValidationErrors{{Index: index, Line: node.Content[0].Line, Message: err.Error()}}
+		}
+		m, ok := obj.(map[string]interface{})
+		if !ok {
+			return nil, ValidationErrors{{Index: index, Line: node.Content[0].Line, Message: "invalid object, it's not a map"}}
+		}
+		doc.obj = m
+		doc.gvk, _ = objectKind(m)
+	}
+
+	return docs, nil
+}
+
+// nodeToObject converts a YAML node to an object as the JSON decoder does
+func nodeToObject(node *yamlv3.Node) (interface{}, error) {
+	data, err := yamlv3.Marshal(node)
+	if err != nil {
+		return nil, err
+	}
+	data, err = yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	var obj interface{}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// errorf returns a validation error for the given field of the document,
+// locating the line of the field
+func (d *document) errorf(field, message string) ValidationError {
+	return ValidationError{
+		Index:   d.index,
+		Line:    d.line(field),
+		Kind:    d.gvk.Kind,
+		Field:   field,
+		Message: message,
+	}
+}
+
+var fieldPathToken = regexp.MustCompile(`[^.\[\]]+|\[\d+\]`)
+
+// line returns the line of the given field path in the document. If the field
+// does not exists, it's the line of the closest parent
+func (d *document) line(field string) int {
+	node := d.node
+	if node == nil || len(node.Content) == 0 {
+		return 0
+	}
+	node = node.Content[0]
+	line := node.Line
+	for _, token := range fieldPathToken.FindAllString(field, -1) {
+		var next *yamlv3.Node
+		switch {
+		case strings.HasPrefix(token, "[") && node.Kind == yamlv3.SequenceNode:
+			i, _ := strconv.Atoi(strings.Trim(token, "[]"))
+			if i < len(node.Content) {
+				next = node.Content[i]
+				line = next.Line
+			}
+		case node.Kind == yamlv3.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if node.Content[i].Value == token {
+					line = node.Content[i].Line
+					next = node.Content[i+1]
+					break
+				}
+			}
+		}
+		if next == nil {
+			return line
+		}
+		node = next
+	}
+	return line
+}
+
+func objectKind(obj map[string]interface{}) (schema.GroupVersionKind, []string) {
+	var errs []string
+	var gv schema.GroupVersion
+	switch apiVersion := obj["apiVersion"].(type) {
+	case nil:
+		errs = append(errs, "apiVersion not set")
+	case string:
+		var err error
+		if gv, err = schema.ParseGroupVersion(apiVersion); err != nil {
+			errs = append(errs, err.Error())
+		}
+	default:
+		errs = append(errs, "apiVersion isn't string type")
+	}
+	kind, ok := obj["kind"].(string)
+	if obj["kind"] == nil {
+		errs = append(errs, "kind not set")
+	} else if !ok {
+		errs = append(errs, "kind isn't string type")
+	}
+	return gv.WithKind(kind), errs
+}
+
+func objectName(obj map[string]interface{}) string {
+	metadata, _ := obj["metadata"].(map[string]interface{})
+	name, _ := metadata["name"].(string)
+	return name
+}
@@ -0,0 +1,74 @@
+package klient
+
+import (
+	"io/ioutil"
+	"testing"
+)
+
+func TestValidator_Validate(t *testing.T) {
+	deployment, err := ioutil.ReadFile("./testdata/validate/deployment.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	crd, err := ioutil.ReadFile("./testdata/validate/crd.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v, err := NewValidatorForVersion("v1.16.3")
+	if err != nil {
+		t.Fatalf("NewValidatorForVersion() error = %v", err)
+	}
+	if err := v.AddCRDs(crd); err != nil {
+		t.Fatalf("Validator.AddCRDs() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		content []byte
+		want    []ValidationError
+	}{
+		{"valid configMap", []byte(`{"apiVersion": "v1", "kind": "ConfigMap", "metadata": { "name": "test-validate-0" }, "data": {	"key1": "apple" } }`), nil},
+		{"deployment", deployment, []ValidationError{
+			{Index: 0, Line: 6, Kind: "Deployment", Name: "test-validate", Field: "spec.replica", Message: "unknown field"},
+			{Index: 0, Line: 19, Kind: "Deployment", Name: "test-validate", Field: "spec.template.spec.containers[0].ports[0].containerPort", Message: `invalid type, got "string", expected "integer"`},
+		}},
+		{"multiple documents", []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: b\ndata: 1\n"), []ValidationError{
+			{Index: 1, Line: 10, Kind: "ConfigMap", Name: "b", Field: "data", Message: `invalid type, got "number", expected "map"`},
+		}},
+		{"custom resource", []byte("apiVersion: example.com/v1\nkind: Fruit\nmetadata:\n  name: apple\nspec:\n  colour: red\n  weight: heavy\n"), []ValidationError{
+			{Index: 0, Line: 5, Kind: "Fruit", Name: "apple", Field: "spec.color", Message: "missing required field"},
+			{Index: 0, Line: 6, Kind: "Fruit", Name: "apple", Field: "spec.colour", Message: "unknown field"},
+			{Index: 0, Line: 7, Kind: "Fruit", Name: "apple", Field: "spec.weight", Message: `invalid type, got "string", expected "integer"`},
+		}},
+		{"missing kind", []byte("apiVersion: v1\nmetadata:\n  name: a\n"), []ValidationError{
+			{Index: 0, Line: 1, Name: "a", Message: "kind not set"},
+		}},
+		{"invalid YAML", []byte("apiVersion: v1\nkind: ConfigMap\n  metadata: a\n"), []ValidationError{
+			{Index: 0, Line: 3, Message: "line 3: mapping values are not allowed in this context"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.Validate(tt.content)
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("Validator.Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			got, ok := err.(ValidationErrors)
+			if !ok {
+				t.Fatalf("Validator.Validate() error = %v, want ValidationErrors", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Validator.Validate() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Validator.Validate() error[%d] = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package klient
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+// Wait waits for the condition on the objects of the given resource, like
+// `kubectl wait`. The condition is `delete`, to wait for the objects to be
+// deleted, or `condition=NAME[=VALUE]`, to wait for the status condition NAME
+// to have the value VALUE, `True` if not set. If the timeout is zero the
+// condition is checked once. Create the resources with `ResultForArgs` or
+// `ResultForFilenameParam`
+func (c *Client) Wait(r *resource.Result, condition string, timeout time.Duration) error {
+	check, err := waitCondition(condition)
+	if err != nil {
+		return err
+	}
+	if err := r.Err(); err != nil {
+		return err
+	}
+
+	// The objects not found are already deleted
+	infos, err := r.Infos()
+	if condition == "delete" {
+		err = utilerrors.FilterOut(err, errors.IsNotFound)
+	}
+	if err != nil {
+		return err
+	}
+	if len(infos) == 0 {
+		if condition == "delete" {
+			return nil
+		}
+		return fmt.Errorf("no matching resources found")
+	}
+
+	deadline := time.Now().Add(timeout)
+	errs := []error{}
+	for _, info := range infos {
+		if err := waitForCondition(info, check, deadline); err != nil {
+			errs = append(errs, fmt.Errorf("timed out waiting for the condition %q on %s %q. %s", condition, info.Mapping.GroupVersionKind.Kind, info.Name, err))
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// conditionFunc returns true if the condition is met on the object, or the
+// reason it's not. The object is nil if it was not found
+type conditionFunc func(info *resource.Info, obj runtime.Object) (bool, string)
+
+// waitCondition returns the function to check the given condition
+func waitCondition(condition string) (conditionFunc, error) {
+	if condition == "delete" {
+		return deleted, nil
+	}
+	if !strings.HasPrefix(condition, "condition=") {
+		return nil, fmt.Errorf("unrecognized condition: %q, expected `delete` or `condition=NAME[=VALUE]`", condition)
+	}
+
+	name, value := strings.TrimPrefix(condition, "condition="), "True"
+	if i := strings.Index(name, "="); i != -1 {
+		name, value = name[:i], name[i+1:]
+	}
+	if name == "" {
+		return nil, fmt.Errorf("unrecognized condition: %q, the condition name is empty", condition)
+	}
+	return func(info *resource.Info, obj runtime.Object) (bool, string) {
+		if obj == nil {
+			return false, "the object was not found"
+		}
+		return hasCondition(obj, name, value)
+	}, nil
+}
+
+// deleted is the condition met when the object is not found or it was
+// replaced by a new one, with a different UID. The objects from manifests have
+// no UID, they are never considered replaced
+func deleted(info *resource.Info, obj runtime.Object) (bool, string) {
+	if obj == nil {
+		return true, ""
+	}
+	current, err := meta.Accessor(obj)
+	if err != nil {
+		return false, err.Error()
+	}
+	original, err := meta.Accessor(info.Object)
+	if err == nil && original.GetUID() != "" && current.GetUID() != original.GetUID() {
+		return true, ""
+	}
+	return false, "the object still exists"
+}
+
+// hasCondition returns true if the object has the status condition with the
+// given value. The name and the value are case insensitive, like kubectl
+func hasCondition(obj runtime.Object, name, value string) (bool, string) {
+	conditions, _, _ := unstructured.NestedSlice(unstructuredContent(obj), "status", "conditions")
+	for _, c := range conditions {
+		cond, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		condType, _, _ := unstructured.NestedString(cond, "type")
+		if !strings.EqualFold(condType, name) {
+			continue
+		}
+		status, _, _ := unstructured.NestedString(cond, "status")
+		if strings.EqualFold(status, value) {
+			return true, ""
+		}
+		return false, fmt.Sprintf("the condition %s is %s", condType, status)
+	}
+	return false, fmt.Sprintf("the condition %s was not found", name)
+}
+
+// waitForCondition checks the condition on the object until it's met or the
+// deadline is reached, returning the reason the condition is not met
+func waitForCondition(info *resource.Info, check conditionFunc, deadline time.Time) error {
+	helper := resource.NewHelper(info.Client, info.Mapping)
+	for {
+		obj, err := helper.Get(info.Namespace, info.Name, false)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		if errors.IsNotFound(err) {
+			obj = nil
+		}
+		met, reason := check(info, obj)
+		if met {
+			return nil
+		}
+		if time.Now().Add(readinessInterval).After(deadline) {
+			return fmt.Errorf("%s", reason)
+		}
+		time.Sleep(readinessInterval)
+	}
+}
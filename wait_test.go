@@ -0,0 +1,112 @@
+package klient
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+func TestWaitCondition(t *testing.T) {
+	ready := testInfo(t, `{"apiVersion": "apps/v1", "kind": "Deployment", "metadata": {"name": "web", "namespace": "apps", "uid": "1"},
+		"status": {"conditions": [{"type": "Available", "status": "True"}, {"type": "Progressing", "status": "False"}]}}`)
+	replaced := testInfo(t, `{"apiVersion": "apps/v1", "kind": "Deployment", "metadata": {"name": "web", "namespace": "apps", "uid": "2"}}`)
+
+	tests := []struct {
+		name      string
+		condition string
+		info      *resource.Info
+		exists    bool
+		wantMet   bool
+		wantErr   bool
+	}{
+		{"condition met", "condition=Available", ready, true, true, false},
+		{"case insensitive", "condition=available=true", ready, true, true, false},
+		{"condition value", "condition=Progressing=False", ready, true, true, false},
+		{"condition not met", "condition=Progressing", ready, true, false, false},
+		{"condition not found", "condition=Ready", ready, true, false, false},
+		{"condition object not found", "condition=Available", ready, false, false, false},
+		{"delete not found", "delete", ready, false, true, false},
+		{"delete exists", "delete", ready, true, false, false},
+		{"delete replaced", "delete", replaced, true, true, false},
+		{"unknown condition", "ready", ready, true, false, true},
+		{"empty condition name", "condition=", ready, true, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check, err := waitCondition(tt.condition)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("waitCondition() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			obj := ready.Object
+			if !tt.exists {
+				obj = nil
+			}
+			if met, reason := check(tt.info, obj); met != tt.wantMet {
+				t.Errorf("waitCondition() met = %v (%s), want %v", met, reason, tt.wantMet)
+			}
+		})
+	}
+}
+
+func TestWaitForCondition(t *testing.T) {
+	readinessInterval = 10 * time.Millisecond
+	defer func() { readinessInterval = 2 * time.Second }()
+
+	requests := 0
+	c := testServerClient(t, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !strings.HasSuffix(req.URL.Path, "/namespaces/test/deployments/web") {
+			http.NotFound(w, req)
+			return
+		}
+		requests++
+		status := "False"
+		if requests >= 3 {
+			status = "True"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"apiVersion": "apps/v1", "kind": "Deployment", "metadata": {"name": "web", "namespace": "test", "uid": "2"},
+			"status": {"conditions": [{"type": "Available", "status": "` + status + `"}]}}`))
+	}))
+
+	tests := []struct {
+		name      string
+		object    string
+		uid       string
+		condition string
+		timeout   time.Duration
+		wantErr   bool
+	}{
+		{"condition met", "web", "", "condition=Available", time.Second, false},
+		{"timeout", "web", "", "condition=Progressing", 50 * time.Millisecond, true},
+		{"deleted", "db", "", "delete", 0, false},
+		{"replaced", "web", "1", "delete", 0, false},
+		{"not deleted", "web", "2", "delete", 0, true},
+		{"not deleted from manifest", "web", "", "delete", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := testInfo(t, `{"apiVersion": "apps/v1", "kind": "Deployment", "metadata": {"name": "`+tt.object+`", "namespace": "test", "uid": "`+tt.uid+`"}}`)
+			info.Mapping.Resource.Resource = "deployments"
+			client, err := c.factory.ClientForMapping(info.Mapping)
+			if err != nil {
+				t.Fatal(err)
+			}
+			info.Client = client
+
+			check, err := waitCondition(tt.condition)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = waitForCondition(info, check, time.Now().Add(tt.timeout))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("waitForCondition() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}